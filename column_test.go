@@ -1,6 +1,10 @@
 package grizzly
 
-import "testing"
+import (
+	"math"
+	"strings"
+	"testing"
+)
 
 func TestNewColumnValidityLengthMismatch(t *testing.T) {
 	if _, err := NewInt64Column("x", []int64{1, 2}, []bool{true}); err == nil {
@@ -28,3 +32,101 @@ func TestValuesAreCopy(t *testing.T) {
 		t.Fatalf("expected column to remain immutable, got %d", got)
 	}
 }
+
+func TestNarrowNumericColumns(t *testing.T) {
+	ids := MustNewInt32Column("id", []int32{3, 1, 2, 1}, nil)
+	temp := MustNewFloat32Column("temp", []float32{20.5, 21, 0, 19.25}, []bool{true, true, false, true})
+	if _, err := NewUInt16Column("x", []uint16{1, 2}, []bool{true}); err == nil {
+		t.Fatalf("expected error")
+	}
+	df, err := NewDataFrame(ids, temp)
+	if err != nil {
+		t.Fatalf("new dataframe: %v", err)
+	}
+	df, err = df.WithColumns(Col("id").Mul(10).Alias("id10"), Col("temp").Add(Col("id")).Alias("sum"))
+	if err != nil {
+		t.Fatalf("with columns: %v", err)
+	}
+	s, _ := df.Column("id10")
+	if c, ok := s.Int64(); !ok || c.Value(0) != 30 {
+		t.Fatalf("expected int64 id10 = 30, got %s %s", s.DType(), s.ValueString(0))
+	}
+	s, _ = df.Column("sum")
+	if c, ok := s.Float64(); !ok || c.Value(0) != 23.5 || !c.IsNull(2) {
+		t.Fatalf("expected float64 sum, got %s", s.DType())
+	}
+	df, err = df.Filter(Col("id").Eq(1))
+	if err != nil {
+		t.Fatalf("filter: %v", err)
+	}
+	s, _ = df.Column("id")
+	c, ok := s.Int32()
+	if !ok || c.Len() != 2 {
+		t.Fatalf("expected two int32 rows, got %s len %d", s.DType(), s.Len())
+	}
+	v := c.Values()
+	v[0] = 99
+	if c.Value(0) != 1 {
+		t.Fatalf("expected column to remain immutable")
+	}
+}
+
+func TestCastStrictAndLenient(t *testing.T) {
+	raw := MustNewUtf8Column("n", []string{"12", "x", "", "300"}, []bool{true, true, false, true})
+	df, err := NewDataFrame(raw)
+	if err != nil {
+		t.Fatalf("new dataframe: %v", err)
+	}
+	s, _ := df.Column("n")
+
+	lenient, err := s.Cast(Int(64), false)
+	if err != nil {
+		t.Fatalf("lenient cast: %v", err)
+	}
+	ints, ok := lenient.Int64()
+	if !ok || ints.Value(0) != 12 || !ints.IsNull(1) || !ints.IsNull(2) || ints.Value(3) != 300 {
+		t.Fatalf("unexpected lenient result %v", ints.Values())
+	}
+	if _, err := s.Cast(Int(64), true); err == nil || !strings.Contains(err.Error(), `row 1 value "x"`) {
+		t.Fatalf("expected strict cast to report row 1, got %v", err)
+	}
+	if _, err := lenient.Cast(UInt(8), true); err == nil || !strings.Contains(err.Error(), "row 3") {
+		t.Fatalf("expected strict cast to reject 300 as uint8, got %v", err)
+	}
+	back, err := lenient.Cast(Utf8(), true)
+	if err != nil || back.ValueString(3) != "300" || !back.IsNull(1) {
+		t.Fatalf("unexpected cast back to utf8: %v", err)
+	}
+	b, err := lenient.Cast(Bool(), true)
+	if err != nil || b.ValueString(0) != "true" {
+		t.Fatalf("unexpected int64 to bool cast: %v", err)
+	}
+
+	if _, err := df.WithColumns(Col("n").Cast(Float(64), true).Alias("f")); err == nil {
+		t.Fatalf("expected strict cast expression to fail")
+	}
+	out, err := df.WithColumns(Col("n").Cast(Float(32), false).Alias("f"))
+	if err != nil {
+		t.Fatalf("lenient cast expression: %v", err)
+	}
+	f, _ := out.Column("f")
+	if fc, ok := f.Float32(); !ok || fc.Value(3) != 300 || !fc.IsNull(1) {
+		t.Fatalf("unexpected float32 column %s", f.DType())
+	}
+
+	wdf, err := NewDataFrame(MustNewFloat64Column("w", []float64{1.5, 1e39, math.Inf(-1)}, nil))
+	if err != nil {
+		t.Fatalf("new dataframe: %v", err)
+	}
+	wide, _ := wdf.Column("w")
+	if _, err := wide.Cast(Float(32), true); err == nil || !strings.Contains(err.Error(), "row 1") {
+		t.Fatalf("expected strict cast to reject 1e39 as float32, got %v", err)
+	}
+	narrow, err := wide.Cast(Float(32), false)
+	if err != nil {
+		t.Fatalf("lenient float32 cast: %v", err)
+	}
+	if fc, ok := narrow.Float32(); !ok || fc.Value(0) != 1.5 || !fc.IsNull(1) || !math.IsInf(float64(fc.Value(2)), -1) {
+		t.Fatalf("unexpected float32 narrowing of %s", narrow.DType())
+	}
+}
@@ -2,9 +2,13 @@ package grizzly
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestScanCSVInferenceNulls(t *testing.T) {
@@ -51,6 +55,174 @@ func TestScanCSVFilterPushdownEven(t *testing.T) {
 	}
 }
 
+func TestScanCSVPredicatePushdownMatchesInMemory(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "wide.csv")
+	var b strings.Builder
+	b.WriteString("id,kind,score,note\n")
+	for i := 0; i < 30000; i++ {
+		score := fmt.Sprint(i % 97)
+		if i%13 == 0 {
+			score = ""
+		}
+		fmt.Fprintf(&b, "%d,%s,%s,n%d\n", i, []string{"a", "b", "c"}[i%3], score, i)
+	}
+	if err := os.WriteFile(p, []byte(b.String()), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	pred := Or(And(Col("kind").Eq("b"), Col("score").Gt(90)), Col("id").Lt(5))
+
+	full, err := ScanCSV(p, ScanOptions{}).Collect()
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	want, err := full.Filter(pred)
+	if err != nil {
+		t.Fatalf("filter: %v", err)
+	}
+	want, _ = want.Select("id", "note")
+
+	lf := ScanCSV(p, ScanOptions{}).Filter(pred).Select("id", "note")
+	plan, err := lf.Explain()
+	if err != nil {
+		t.Fatalf("explain: %v", err)
+	}
+	if !strings.Contains(plan, "projection=[id,note] predicate(cols=[kind,score,id])") || strings.Contains(plan, "filter(") {
+		t.Fatalf("predicate not pushed into scan:\n%s", plan)
+	}
+	for _, procs := range []int{1, 4} {
+		prev := runtime.GOMAXPROCS(procs)
+		got, err := lf.Collect()
+		runtime.GOMAXPROCS(prev)
+		if err != nil {
+			t.Fatalf("collect procs=%d: %v", procs, err)
+		}
+		if got.Height() == 0 || joinRows(got) != joinRows(want) {
+			t.Fatalf("procs=%d: pushed-down result differs from in-memory filter (%d vs %d rows)", procs, got.Height(), want.Height())
+		}
+	}
+}
+
+func TestScanCSVStrictCastFilterReportsFileRow(t *testing.T) {
+	const n = 20000
+	var b strings.Builder
+	b.WriteString("k,s\n")
+	for i := 0; i < n; i++ {
+		s := fmt.Sprint(i % 10)
+		if i == 15000 {
+			s = "x"
+		}
+		fmt.Fprintf(&b, "%d,%s\n", i%2, s)
+	}
+	p := filepath.Join(t.TempDir(), "strict.csv")
+	if err := os.WriteFile(p, []byte(b.String()), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	opts := ScanOptions{DTypes: map[string]DataType{"s": Utf8()}}
+	strict := Col("s").Cast(Int(64), true).Eq(3)
+
+	// Neither a later cheaper filter nor the scan may shrink the rows the
+	// strict cast sees.
+	lazy := []*LazyFrame{
+		ScanCSV(p, opts).Filter(strict).Filter(Col("k").Eq(0)),
+		ScanCSV(p, opts).Filter(And(Col("k").Eq(0), strict)),
+	}
+	for i, lf := range lazy {
+		_, err := lf.Collect()
+		if err == nil || !strings.Contains(err.Error(), `row 15000 value "x"`) {
+			t.Fatalf("plan %d: expected the strict cast to fail on row 15000, got %v", i, err)
+		}
+	}
+}
+
+func TestScanCSVKeepsFillFilterOutOfScan(t *testing.T) {
+	const n = 30000
+	var b strings.Builder
+	b.WriteString("a,x\n")
+	for i := 0; i < n; i++ {
+		if i%5000 == 0 {
+			fmt.Fprintf(&b, "%d,%d\n", i, i)
+		} else {
+			fmt.Fprintf(&b, "%d,\n", i)
+		}
+	}
+	p := filepath.Join(t.TempDir(), "sparse.csv")
+	if err := os.WriteFile(p, []byte(b.String()), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	// A forward fill evaluated per chunk would leave the leading rows of
+	// most chunks NULL.
+	ffill := Col("x").FillNullStrategy(FillForward).Gt(-1)
+	lf := ScanCSV(p, ScanOptions{}).Filter(And(Col("a").Gt(1), ffill))
+	plan, err := lf.Explain()
+	if err != nil {
+		t.Fatalf("explain: %v", err)
+	}
+	if strings.Contains(plan, "predicate(") || !strings.Contains(plan, "filter(") {
+		t.Fatalf("expected the filter to stay out of the scan:\n%s", plan)
+	}
+	df, err := lf.Collect()
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	if df.Height() != n-2 {
+		t.Fatalf("expected %d rows, got %d", n-2, df.Height())
+	}
+
+	// Filters stay behind the sort that orders the rows they fill from.
+	bfill := Col("x").FillNullStrategy(FillBackward).Gt(-1)
+	df, err = ScanCSV(p, ScanOptions{}).Sort("a", true).Filter(bfill).Filter(Col("a").Lt(10)).Collect()
+	if err != nil {
+		t.Fatalf("collect sorted: %v", err)
+	}
+	if df.Height() != 10 {
+		t.Fatalf("expected 10 rows after sorted fill, got %d", df.Height())
+	}
+
+	// Nor may a later Slice cap the rows a mean fill averages.
+	df, err = ScanCSV(p, ScanOptions{}).WithColumns(Col("x").FillNullStrategy(FillMean)).Slice(1, 1).Collect()
+	if err != nil {
+		t.Fatalf("collect slice: %v", err)
+	}
+	if got := joinRows(df); got != "1,12500" {
+		t.Fatalf("unexpected slice %s", got)
+	}
+}
+
+func TestScanCSVHeadStopsEarly(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "x.csv")
+	var b strings.Builder
+	b.WriteString("id,name\n")
+	for i := 0; i < 20000; i++ {
+		fmt.Fprintf(&b, "%d,n%d\n", i, i)
+	}
+	// A malformed row well past the limit fails any full read.
+	b.WriteString("1,2,3\n")
+	if err := os.WriteFile(p, []byte(b.String()), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := ScanCSV(p, ScanOptions{}).Collect(); err == nil {
+		t.Fatalf("expected malformed row error on a full scan")
+	}
+	for _, procs := range []int{1, 4} {
+		prev := runtime.GOMAXPROCS(procs)
+		head, err := ScanCSV(p, ScanOptions{}).Select("name").Slice(9000, 3).Collect()
+		filtered, ferr := ScanCSV(p, ScanOptions{}).Filter(Col("id").Gt(100)).Head(2).Collect()
+		runtime.GOMAXPROCS(prev)
+		if err != nil || ferr != nil {
+			t.Fatalf("procs=%d: %v / %v", procs, err, ferr)
+		}
+		if got := joinRows(head); got != "n9000;n9001;n9002" {
+			t.Fatalf("procs=%d: unexpected slice %s", procs, got)
+		}
+		if got := joinRows(filtered); got != "101,n101;102,n102" {
+			t.Fatalf("procs=%d: unexpected filtered head %s", procs, got)
+		}
+	}
+}
+
 func TestScanCSVCollectContextCanceled(t *testing.T) {
 	dir := t.TempDir()
 	p := filepath.Join(dir, "x.csv")
@@ -68,3 +240,212 @@ func TestScanCSVCollectContextCanceled(t *testing.T) {
 		t.Fatalf("expected context.Canceled, got %v", err)
 	}
 }
+
+func TestScanCSVTemporalInference(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "x.csv")
+	data := "day,ts,local,eu\n" +
+		"2024-01-02,2024-01-02T10:00:00.5Z,2024-01-02 10:00:00,02/01/2024\n" +
+		"2024-01-03,2024-01-03T09:30:00+02:00,2024-01-03 11:00:00,03/01/2024\n" +
+		",NULL,,\n"
+	if err := os.WriteFile(p, []byte(data), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	lf := ScanCSV(p, ScanOptions{DateFormats: map[string]string{"eu": "02/01/2006"}})
+	df, err := lf.Collect()
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	want := map[string]string{"day": "date", "ts": "datetime[us,UTC]", "local": "datetime[us]", "eu": "date"}
+	for name, dt := range want {
+		f, ok := df.Schema().Lookup(name)
+		if !ok || f.Type.String() != dt {
+			t.Fatalf("column %s: got %v want %s", name, f.Type, dt)
+		}
+	}
+	s, _ := df.Column("ts")
+	ts, _ := s.Datetime()
+	if got := ts.Value(1); !got.Equal(time.Date(2024, 1, 3, 7, 30, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected offset handling: %v", got)
+	}
+	if !ts.IsNull(2) {
+		t.Fatalf("expected null timestamp")
+	}
+	s, _ = df.Column("eu")
+	if eu, _ := s.Date(); !eu.Value(0).Equal(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected explicit format parse: %v", eu.Value(0))
+	}
+
+	out, err := lf.Filter(Col("local").Gte(time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC))).Collect()
+	if err != nil {
+		t.Fatalf("filter: %v", err)
+	}
+	if out.Height() != 1 {
+		t.Fatalf("expected 1 row got %d", out.Height())
+	}
+}
+
+func TestScanCSVNarrowDTypes(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "sensors.csv")
+	data := "sensor,reading,level,id\na,1.5,3,18446744073709551615\nb,2.25,250,7\na,,9,8\nb,4,1,9\n"
+	if err := os.WriteFile(p, []byte(data), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	opts := ScanOptions{DTypes: map[string]DataType{
+		"reading": Float(32), "level": UInt(8), "id": UInt(64),
+	}}
+	df, err := ScanCSV(p, opts).Filter(Col("level").Gt(2)).Sort("level", true).Collect()
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	s, _ := df.Column("level")
+	level, ok := s.UInt8()
+	if !ok {
+		t.Fatalf("expected uint8 level, got %s", s.DType())
+	}
+	if got := level.Values(); len(got) != 3 || got[0] != 250 || got[1] != 9 || got[2] != 3 {
+		t.Fatalf("unexpected levels %v", got)
+	}
+	s, _ = df.Column("id")
+	if id, ok := s.UInt64(); !ok || id.Value(2) != 18446744073709551615 {
+		t.Fatalf("expected uint64 id to round-trip, got %s", s.ValueString(2))
+	}
+	out, err := df.MarshalRowsJSON()
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !strings.Contains(string(out), `"reading":2.25,"level":250,"id":7}`) {
+		t.Fatalf("unexpected json %s", out)
+	}
+
+	gb, err := df.GroupBy("sensor")
+	if err != nil {
+		t.Fatalf("groupby: %v", err)
+	}
+	agg, err := gb.Agg(Sum("level"), Max("level"), Mean("reading"))
+	if err != nil {
+		t.Fatalf("agg: %v", err)
+	}
+	agg, err = agg.SortBy("sensor", false)
+	if err != nil {
+		t.Fatalf("sort: %v", err)
+	}
+	schema := agg.Schema()
+	if got := fmt.Sprint(schema.Fields[1].Type, schema.Fields[2].Type, schema.Fields[3].Type); got != "int64 uint8 float64" {
+		t.Fatalf("unexpected agg dtypes %s", got)
+	}
+	s, _ = agg.Column(schema.Fields[1].Name)
+	sums, _ := s.Int64()
+	if sums.Value(0) != 12 || sums.Value(1) != 250 {
+		t.Fatalf("unexpected sums %v", sums.Values())
+	}
+
+	_, err = ScanCSV(p, ScanOptions{DTypes: map[string]DataType{"level": Int(12)}}).Collect()
+	if err == nil {
+		t.Fatalf("expected error for unsupported dtype")
+	}
+	if _, err = ScanCSV(p, ScanOptions{DTypes: map[string]DataType{"id": Int(8)}}).Collect(); err == nil {
+		t.Fatalf("expected error for out-of-range value")
+	}
+}
+
+func TestWriteCSVOptions(t *testing.T) {
+	name := MustNewUtf8Column("name", []string{"plain", "a,b", `say "hi"`, "", "x"}, []bool{true, true, true, true, false})
+	score := MustNewFloat64Column("score", []float64{1.5, 2, 1.0 / 3, 0, 4}, []bool{true, true, true, false, true})
+	df, err := NewDataFrame(name, score)
+	if err != nil {
+		t.Fatalf("new dataframe: %v", err)
+	}
+
+	var b strings.Builder
+	if err := df.WriteCSV(&b, CSVWriteOptions{}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	want := "name,score\nplain,1.5\n\"a,b\",2\n\"say \"\"hi\"\"\",0.3333333333333333\n\"\",\n,4\n"
+	if b.String() != want {
+		t.Fatalf("unexpected csv:\n%s", b.String())
+	}
+
+	b.Reset()
+	opts := CSVWriteOptions{Delimiter: ';', Quote: QuoteNonNumeric, NullValue: "NA", NoHeader: true, FloatPrecision: 2}
+	if err := df.WriteCSV(&b, opts); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	want = "\"plain\";1.50\n\"a,b\";2.00\n\"say \"\"hi\"\"\";0.33\n\"\";NA\nNA;4.00\n"
+	if b.String() != want {
+		t.Fatalf("unexpected csv with options:\n%s", b.String())
+	}
+	if err := df.WriteCSV(&b, CSVWriteOptions{Delimiter: '"'}); err == nil {
+		t.Fatalf("expected error for quote delimiter")
+	}
+}
+
+func TestWriteCSVRoundTripsLargeFrame(t *testing.T) {
+	const n = 50000
+	ids := make([]int64, n)
+	labels := make([]string, n)
+	valid := make([]bool, n)
+	for i := range ids {
+		ids[i] = int64(i)
+		labels[i] = fmt.Sprintf("row %d, \"q\"\nnext", i)
+		valid[i] = i%7 != 0
+	}
+	df, err := NewDataFrame(MustNewInt64Column("id", ids, nil), MustNewUtf8Column("label", labels, valid))
+	if err != nil {
+		t.Fatalf("new dataframe: %v", err)
+	}
+	p := filepath.Join(t.TempDir(), "out.csv")
+	f, err := os.Create(p)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := df.WriteCSV(f, CSVWriteOptions{}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	back, err := ScanCSV(p, ScanOptions{}).Collect()
+	if err != nil {
+		t.Fatalf("read back: %v", err)
+	}
+	if back.ProjectionChecksum(2) != df.ProjectionChecksum(2) {
+		t.Fatalf("round trip changed the data")
+	}
+}
+
+// failingWriter accepts ok writes and fails every one after.
+type failingWriter struct {
+	ok, calls int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	w.calls++
+	if w.calls > w.ok {
+		return 0, fmt.Errorf("disk full")
+	}
+	return len(p), nil
+}
+
+func TestWriteCSVStopsAtFirstWriteError(t *testing.T) {
+	ids := make([]int64, 200000)
+	for i := range ids {
+		ids[i] = int64(i)
+	}
+	df, err := NewDataFrame(MustNewInt64Column("id", ids, nil))
+	if err != nil {
+		t.Fatalf("new dataframe: %v", err)
+	}
+	for _, procs := range []int{1, 4} {
+		prev := runtime.GOMAXPROCS(procs)
+		w := &failingWriter{ok: 2}
+		err := df.WriteCSV(w, CSVWriteOptions{})
+		runtime.GOMAXPROCS(prev)
+		if err == nil || err.Error() != "disk full" {
+			t.Fatalf("procs=%d: expected the write error, got %v", procs, err)
+		}
+		if w.calls != 3 {
+			t.Fatalf("procs=%d: expected writing to stop at the failed call, got %d calls", procs, w.calls)
+		}
+	}
+}
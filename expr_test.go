@@ -0,0 +1,283 @@
+package grizzly
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWithColumnsArithmetic(t *testing.T) {
+	a, _ := NewInt64Column("a", []int64{7, -7, 4, 0}, []bool{true, true, true, false})
+	b, _ := NewInt64Column("b", []int64{2, 2, 0, 1}, nil)
+	c := MustNewFloat64Column("c", []float64{0.5, 1, 2, 3}, nil)
+	df, err := NewDataFrame(a, b, c)
+	if err != nil {
+		t.Fatalf("new dataframe: %v", err)
+	}
+	out, err := df.WithColumns(
+		Col("a").Add(Col("b")).Alias("sum"),
+		Col("a").Mod(Col("b")).Alias("mod"),
+		Col("a").Div(Col("b")).Alias("div"),
+		Col("a").Mul(Col("c")).Alias("mul"),
+		Col("b").Neg(),
+		Lit(1).Sub(Col("b")).Alias("one_minus"),
+		Col("c").Cast(Int(64), false).Alias("ci"),
+	)
+	if err != nil {
+		t.Fatalf("with columns: %v", err)
+	}
+	wantTypes := map[string]DataType{"sum": Int(64), "mod": Int(64), "div": Float(64), "mul": Float(64), "b": Int(64), "one_minus": Int(64), "ci": Int(64)}
+	for name, want := range wantTypes {
+		s, ok := out.Column(name)
+		if !ok || s.DType() != want {
+			t.Fatalf("column %s: ok=%v dtype=%v want %v", name, ok, s.DType(), want)
+		}
+	}
+	sel, err := out.Select("sum", "mod", "div", "mul", "b", "one_minus", "ci")
+	if err != nil {
+		t.Fatalf("select: %v", err)
+	}
+	want := "9,1,3.5,3.5,-2,-1,0;-5,-1,-3.5,-7,-2,-1,1;4,null,+Inf,8,0,1,2;null,null,null,null,-1,0,3"
+	if got := joinRows(sel); got != want {
+		t.Fatalf("unexpected rows\n got %s\nwant %s", got, want)
+	}
+
+	if _, err := df.WithColumns(Col("missing").Add(1)); err == nil {
+		t.Fatalf("expected unknown column error")
+	}
+}
+
+func TestLazyWithColumns(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "x.csv")
+	data := "price,qty,unused\n2.5,4,x\n1,3,y\n4,1,z\n"
+	if err := os.WriteFile(p, []byte(data), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	lf := ScanCSV(p, ScanOptions{}).
+		WithColumns(Col("price").Mul(Col("qty")).Alias("total")).
+		Filter(Col("total").Gt(3.5)).
+		Select("qty", "total")
+	plan, err := lf.Explain()
+	if err != nil {
+		t.Fatalf("explain: %v", err)
+	}
+	if !strings.Contains(plan, "projection=[price,qty]") || !strings.Contains(plan, "with_columns(total)") {
+		t.Fatalf("unexpected plan:\n%s", plan)
+	}
+	df, err := lf.Collect()
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	if got := joinRows(df); got != "4,10;1,4" {
+		t.Fatalf("unexpected rows %s", got)
+	}
+}
+
+func TestColumnToColumnComparisons(t *testing.T) {
+	ordered, _ := NewInt64Column("ordered", []int64{1, 5, 3, 4}, []bool{true, true, true, false})
+	shipped := MustNewFloat64Column("shipped", []float64{2.5, 5, 1, 9}, nil)
+	a := MustNewUtf8Column("a", []string{"x", "b", "m", "z"}, nil)
+	b := MustNewUtf8Column("b", []string{"y", "b", "a", "z"}, nil)
+	df, err := NewDataFrame(ordered, shipped, a, b)
+	if err != nil {
+		t.Fatalf("new dataframe: %v", err)
+	}
+
+	cases := []struct {
+		name string
+		e    Expr
+		want string
+	}{
+		{"gt mixed numeric", Col("shipped").Gt(Col("ordered")), "1,2.5,x,y"},
+		{"eq mixed numeric", Col("shipped").Eq(Col("ordered")), "5,5,b,b"},
+		{"computed", Col("ordered").Mul(2).Lte(Col("shipped")), "1,2.5,x,y"},
+		{"utf8", Col("a").Lt(Col("b")), "1,2.5,x,y"},
+		{"not with null", Not(Col("shipped").Gt(Col("ordered"))), "5,5,b,b;3,1,m,a"},
+	}
+	for _, tc := range cases {
+		out, err := df.Filter(tc.e)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if got := joinRows(out); got != tc.want {
+			t.Fatalf("%s: got %s want %s", tc.name, got, tc.want)
+		}
+	}
+
+	if _, err := df.Filter(Col("a").Eq(Col("ordered"))); err == nil {
+		t.Fatalf("expected dtype mismatch error")
+	}
+}
+
+func TestStrNamespace(t *testing.T) {
+	name, _ := NewUtf8Column("name", []string{"  Ada Lovelace ", "émile zola", "", "x"}, []bool{true, true, true, false})
+	path := MustNewUtf8Column("path", []string{"a/b/c", "a", "b/c", "c/d"}, nil)
+	df, err := NewDataFrame(name, path)
+	if err != nil {
+		t.Fatalf("new dataframe: %v", err)
+	}
+
+	filters := []struct {
+		e    Expr
+		want int
+	}{
+		{Col("path").Str().Contains("b"), 2},
+		{Col("path").Str().StartsWith("a"), 2},
+		{Col("path").Str().EndsWith("c"), 2},
+		{Col("path").Str().Matches(`^[a-c](/[a-c])+$`), 2},
+		{Col("name").Str().Strip("").Str().Lower().Str().StartsWith("ada"), 1},
+	}
+	for i, tc := range filters {
+		out, err := df.Filter(tc.e)
+		if err != nil {
+			t.Fatalf("filter %d: %v", i, err)
+		}
+		if out.Height() != tc.want {
+			t.Fatalf("filter %d: got %d rows want %d", i, out.Height(), tc.want)
+		}
+	}
+	if _, err := df.Filter(Col("path").Str().Matches("(")); err == nil {
+		t.Fatalf("expected invalid regex error")
+	}
+
+	if got := Col("path").Str().Split("/", -1); got != nil {
+		t.Fatalf("expected no parts for negative n, got %d", len(got))
+	}
+	parts := Col("path").Str().Split("/", 2)
+	out, err := df.WithColumns(
+		Col("name").Str().LenBytes().Alias("bytes"),
+		Col("name").Str().LenChars().Alias("chars"),
+		Col("name").Str().Upper().Alias("upper"),
+		Col("name").Str().Strip(" ").Str().Slice(-4, 3).Alias("slice"),
+		Col("path").Str().Replace("/", "::").Alias("replaced"),
+		parts[0], parts[1],
+		ConcatStr("-", Col("path"), Col("name").Str().LenChars(), "end").Alias("joined"),
+	)
+	if err != nil {
+		t.Fatalf("with columns: %v", err)
+	}
+	sel, err := out.Select("bytes", "chars", "upper", "slice", "replaced", "path_0", "path_1", "joined")
+	if err != nil {
+		t.Fatalf("select: %v", err)
+	}
+	want := "15,15,  ADA LOVELACE ,lac,a::b::c,a,b,a/b/c-15-end;" +
+		"11,10,ÉMILE ZOLA,zol,a,a,null,a-10-end;" +
+		"0,0,,,b::c,b,c,b/c-0-end;" +
+		"null,null,null,null,c::d,c,d,null"
+	if got := joinRows(sel); got != want {
+		t.Fatalf("unexpected rows\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestConditionalExpressions(t *testing.T) {
+	qty, _ := NewInt64Column("qty", []int64{20, 5, 0, 7}, []bool{true, true, true, false})
+	price, _ := NewFloat64Column("price", []float64{1.5, 0, 4, 2}, []bool{true, false, true, true})
+	backup := MustNewFloat64Column("backup", []float64{9, 8, 7, 6}, nil)
+	df, err := NewDataFrame(qty, price, backup)
+	if err != nil {
+		t.Fatalf("new dataframe: %v", err)
+	}
+	out, err := df.WithColumns(
+		When(Col("qty").Gt(10)).Then("bulk").When(Col("qty").Gt(0)).Then("retail").Otherwise("none").Alias("tier"),
+		When(Col("qty").Gt(10)).Then(Col("price")).Otherwise(Col("qty")).Alias("mixed"),
+		When(Col("qty").Eq(0)).Then(1).Otherwise(nil).Alias("zero"),
+		Coalesce(Col("price"), Col("backup")).Alias("coalesced"),
+		Col("qty").FillNull(-1).Alias("qty_fill"),
+		Col("price").FillNullStrategy(FillForward).Alias("ffill"),
+		Col("qty").FillNullStrategy(FillBackward).Alias("bfill"),
+		Col("qty").FillNullStrategy(FillMean).Alias("mean"),
+	)
+	if err != nil {
+		t.Fatalf("with columns: %v", err)
+	}
+	sel, err := out.Select("tier", "mixed", "zero", "coalesced", "qty_fill", "ffill", "bfill", "mean")
+	if err != nil {
+		t.Fatalf("select: %v", err)
+	}
+	want := "bulk,1.5,null,1.5,20,1.5,20,20;" +
+		"retail,5,null,8,5,1.5,5,5;" +
+		"none,0,1,4,0,4,0,0;" +
+		"none,null,null,2,-1,2,null,8.333333333333334"
+	if got := joinRows(sel); got != want {
+		t.Fatalf("unexpected rows\n got %s\nwant %s", got, want)
+	}
+
+	if _, err := df.WithColumns(When(Col("qty").Gt(1)).Then("x").Otherwise(1)); err == nil {
+		t.Fatalf("expected branch dtype mismatch error")
+	}
+}
+
+func TestFilterNonRowLocalConjunct(t *testing.T) {
+	a, _ := NewInt64Column("a", []int64{1, 2, 3, 4}, nil)
+	x, _ := NewInt64Column("x", []int64{10, 0, 0, 0}, []bool{true, false, false, false})
+	df, err := NewDataFrame(a, x)
+	if err != nil {
+		t.Fatalf("new dataframe: %v", err)
+	}
+	// The fill must see row 0 even though the first conjunct drops it.
+	out, err := df.Filter(And(Col("a").Gt(1), Col("x").FillNullStrategy(FillForward).Gt(5)))
+	if err != nil {
+		t.Fatalf("filter: %v", err)
+	}
+	if got := joinRows(out); got != "2,null;3,null;4,null" {
+		t.Fatalf("unexpected rows %s", got)
+	}
+}
+
+func TestUInt64ExactAbove2Pow53(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "big.csv")
+	data := "a,b,s\n18446744073709551615,18446744073709551614,-1\n9007199254740993,9007199254740992,9007199254740993\n5,5,-5\n"
+	if err := os.WriteFile(p, []byte(data), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	df, err := ScanCSV(p, ScanOptions{DTypes: map[string]DataType{"a": UInt(64), "b": UInt(64)}}).Collect()
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+
+	cases := []struct {
+		name string
+		e    Expr
+		want string
+	}{
+		{"column eq", Col("a").Eq(Col("b")), "5,5,-5"},
+		{"column gt", Col("a").Gt(Col("b")), "18446744073709551615,18446744073709551614,-1;9007199254740993,9007199254740992,9007199254740993"},
+		{"uint64 literal", Col("b").Eq(uint64(9007199254740992)), "9007199254740993,9007199254740992,9007199254740993"},
+		{"int64 literal", Col("a").Eq(int64(9007199254740992)), ""},
+		{"negative literal", Col("a").Gt(-1), "18446744073709551615,18446744073709551614,-1;9007199254740993,9007199254740992,9007199254740993;5,5,-5"},
+		{"mixed sign", Col("s").Eq(Col("a")), "9007199254740993,9007199254740992,9007199254740993"},
+		{"mixed sign lt", Col("s").Lt(Col("b")), "18446744073709551615,18446744073709551614,-1;5,5,-5"},
+		{"in", Col("a").In(uint64(18446744073709551615), -3, 9007199254740992), "18446744073709551615,18446744073709551614,-1"},
+	}
+	for _, tc := range cases {
+		out, err := df.Filter(tc.e)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if got := joinRows(out); got != tc.want {
+			t.Fatalf("%s: got %s want %s", tc.name, got, tc.want)
+		}
+	}
+
+	out, err := df.WithColumns(
+		Col("a").Sub(Col("b")).Alias("diff"),
+		Col("b").Add(1).Alias("next"),
+		Col("a").Mod(Lit(uint64(1)<<63)).Alias("mod"),
+	)
+	if err != nil {
+		t.Fatalf("with columns: %v", err)
+	}
+	sel, err := out.Select("diff", "next", "mod")
+	if err != nil {
+		t.Fatalf("select: %v", err)
+	}
+	want := "1,18446744073709551615,9223372036854775807;1,9007199254740993,9007199254740993;0,6,5"
+	if got := joinRows(sel); got != want {
+		t.Fatalf("unexpected rows\n got %s\nwant %s", got, want)
+	}
+	if s, _ := sel.Column("diff"); s.DType() != UInt(64) {
+		t.Fatalf("expected uint64 diff, got %s", s.DType())
+	}
+}
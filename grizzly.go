@@ -3,10 +3,13 @@ package grizzly
 import (
 	"context"
 	"fmt"
+	"io"
+	"time"
 
 	"grizzly/internal/array"
 	"grizzly/internal/exec"
 	"grizzly/internal/expr"
+	csvio "grizzly/internal/io/csv"
 	"grizzly/internal/plan"
 )
 
@@ -79,10 +82,20 @@ type Series struct {
 // This includes Series values taken from a DataFrame as well as concrete
 // column wrappers created via NewInt64Column/NewUtf8Column/etc.
 type Column interface {
+	IntoColumn
 	internalColumn() array.Column
 }
 
+// IntoColumn is anything DataFrame.WithColumns accepts: a materialized Column
+// or a value expression evaluated against the frame.
+type IntoColumn interface {
+	intoColumn(df *exec.DataFrame) (array.Column, error)
+}
+
 func (s Series) internalColumn() array.Column { return s.col }
+func (s Series) intoColumn(*exec.DataFrame) (array.Column, error) {
+	return s.col, nil
+}
 
 func (s Series) Name() string    { return s.col.Name() }
 func (s Series) DType() DataType { return s.col.DType() }
@@ -95,6 +108,23 @@ func (s Series) IsNull(i int) bool {
 // It returns an empty string for NULL.
 func (s Series) ValueString(i int) string { return s.col.ValueString(i) }
 
+// Cast converts the series to dtype, e.g. utf8 to int64 or float64 to utf8.
+// Lenient mode turns values that do not convert into NULL; strict mode
+// returns an error naming the first such row instead.
+func (s Series) Cast(dtype DataType, strict bool) (Series, error) {
+	var col array.Column
+	var err error
+	if strict {
+		col, err = array.CastStrict(s.col, dtype)
+	} else {
+		col, err = array.Cast(s.col, dtype)
+	}
+	if err != nil {
+		return Series{}, err
+	}
+	return Series{col: col}, nil
+}
+
 func (s Series) Int64() (*Int64Column, bool) {
 	c, ok := s.col.(*array.Int64Column)
 	if !ok {
@@ -123,6 +153,27 @@ func (s Series) Utf8() (*Utf8Column, bool) {
 	}
 	return &Utf8Column{col: c}, true
 }
+func (s Series) Date() (*DateColumn, bool) {
+	c, ok := s.col.(*array.DateColumn)
+	if !ok {
+		return nil, false
+	}
+	return &DateColumn{col: c}, true
+}
+func (s Series) Datetime() (*DatetimeColumn, bool) {
+	c, ok := s.col.(*array.DatetimeColumn)
+	if !ok {
+		return nil, false
+	}
+	return &DatetimeColumn{col: c}, true
+}
+func (s Series) Duration() (*DurationColumn, bool) {
+	c, ok := s.col.(*array.DurationColumn)
+	if !ok {
+		return nil, false
+	}
+	return &DurationColumn{col: c}, true
+}
 
 // Concrete column wrappers.
 //
@@ -131,11 +182,25 @@ type Int64Column struct{ col *array.Int64Column }
 type Float64Column struct{ col *array.Float64Column }
 type BoolColumn struct{ col *array.BoolColumn }
 type Utf8Column struct{ col *array.Utf8Column }
-
-func (c *Int64Column) internalColumn() array.Column   { return c.col }
-func (c *Float64Column) internalColumn() array.Column { return c.col }
-func (c *BoolColumn) internalColumn() array.Column    { return c.col }
-func (c *Utf8Column) internalColumn() array.Column    { return c.col }
+type DateColumn struct{ col *array.DateColumn }
+type DatetimeColumn struct{ col *array.DatetimeColumn }
+type DurationColumn struct{ col *array.DurationColumn }
+
+func (c *Int64Column) internalColumn() array.Column    { return c.col }
+func (c *Float64Column) internalColumn() array.Column  { return c.col }
+func (c *BoolColumn) internalColumn() array.Column     { return c.col }
+func (c *Utf8Column) internalColumn() array.Column     { return c.col }
+func (c *DateColumn) internalColumn() array.Column     { return c.col }
+func (c *DatetimeColumn) internalColumn() array.Column { return c.col }
+func (c *DurationColumn) internalColumn() array.Column { return c.col }
+
+func (c *Int64Column) intoColumn(*exec.DataFrame) (array.Column, error)    { return c.col, nil }
+func (c *Float64Column) intoColumn(*exec.DataFrame) (array.Column, error)  { return c.col, nil }
+func (c *BoolColumn) intoColumn(*exec.DataFrame) (array.Column, error)     { return c.col, nil }
+func (c *Utf8Column) intoColumn(*exec.DataFrame) (array.Column, error)     { return c.col, nil }
+func (c *DateColumn) intoColumn(*exec.DataFrame) (array.Column, error)     { return c.col, nil }
+func (c *DatetimeColumn) intoColumn(*exec.DataFrame) (array.Column, error) { return c.col, nil }
+func (c *DurationColumn) intoColumn(*exec.DataFrame) (array.Column, error) { return c.col, nil }
 
 func (c *Int64Column) Name() string      { return c.col.Name() }
 func (c *Int64Column) DType() DataType   { return c.col.DType() }
@@ -165,6 +230,47 @@ func (c *Utf8Column) IsNull(i int) bool  { return c.col.IsNull(i) }
 func (c *Utf8Column) Value(i int) string { return c.col.Value(i) }
 func (c *Utf8Column) Values() []string   { return c.col.Values() }
 
+// Temporal values are returned as time.Time (dates at UTC midnight) or time.Duration.
+
+func (c *DateColumn) Name() string          { return c.col.Name() }
+func (c *DateColumn) DType() DataType       { return c.col.DType() }
+func (c *DateColumn) Len() int              { return c.col.Len() }
+func (c *DateColumn) IsNull(i int) bool     { return c.col.IsNull(i) }
+func (c *DateColumn) Value(i int) time.Time { return c.col.Time(i) }
+func (c *DateColumn) Values() []time.Time {
+	out := make([]time.Time, c.col.Len())
+	for i := range out {
+		out[i] = c.col.Time(i)
+	}
+	return out
+}
+
+func (c *DatetimeColumn) Name() string          { return c.col.Name() }
+func (c *DatetimeColumn) DType() DataType       { return c.col.DType() }
+func (c *DatetimeColumn) Len() int              { return c.col.Len() }
+func (c *DatetimeColumn) IsNull(i int) bool     { return c.col.IsNull(i) }
+func (c *DatetimeColumn) Value(i int) time.Time { return c.col.Time(i) }
+func (c *DatetimeColumn) Values() []time.Time {
+	out := make([]time.Time, c.col.Len())
+	for i := range out {
+		out[i] = c.col.Time(i)
+	}
+	return out
+}
+
+func (c *DurationColumn) Name() string              { return c.col.Name() }
+func (c *DurationColumn) DType() DataType           { return c.col.DType() }
+func (c *DurationColumn) Len() int                  { return c.col.Len() }
+func (c *DurationColumn) IsNull(i int) bool         { return c.col.IsNull(i) }
+func (c *DurationColumn) Value(i int) time.Duration { return c.col.Duration(i) }
+func (c *DurationColumn) Values() []time.Duration {
+	out := make([]time.Duration, c.col.Len())
+	for i := range out {
+		out[i] = c.col.Duration(i)
+	}
+	return out
+}
+
 func NewInt64Column(name string, data []int64, valid []bool) (*Int64Column, error) {
 	c, err := array.NewInt64Column(name, data, valid)
 	if err != nil {
@@ -194,6 +300,45 @@ func NewUtf8Column(name string, data []string, valid []bool) (*Utf8Column, error
 	return &Utf8Column{col: c}, nil
 }
 
+// NewDateColumn stores the calendar date of each value (in its own location).
+func NewDateColumn(name string, data []time.Time, valid []bool) (*DateColumn, error) {
+	days := make([]int32, len(data))
+	for i := range data {
+		days[i] = array.DateFromTime(data[i])
+	}
+	c, err := array.NewDateColumn(name, days, valid)
+	if err != nil {
+		return nil, err
+	}
+	return &DateColumn{col: c}, nil
+}
+
+// NewDatetimeColumn stores instants at the given unit. An empty tz yields a
+// naive datetime column; otherwise tz must be an IANA zone name.
+func NewDatetimeColumn(name string, unit TimeUnit, tz string, data []time.Time, valid []bool) (*DatetimeColumn, error) {
+	raw := make([]int64, len(data))
+	for i := range data {
+		raw[i] = array.TimeToUnit(data[i], unit)
+	}
+	c, err := array.NewDatetimeColumn(name, unit, tz, raw, valid)
+	if err != nil {
+		return nil, err
+	}
+	return &DatetimeColumn{col: c}, nil
+}
+
+func NewDurationColumn(name string, unit TimeUnit, data []time.Duration, valid []bool) (*DurationColumn, error) {
+	raw := make([]int64, len(data))
+	for i := range data {
+		raw[i] = array.DurationToUnit(data[i], unit)
+	}
+	c, err := array.NewDurationColumn(name, unit, raw, valid)
+	if err != nil {
+		return nil, err
+	}
+	return &DurationColumn{col: c}, nil
+}
+
 func MustNewInt64Column(name string, data []int64, valid []bool) *Int64Column {
 	c, err := NewInt64Column(name, data, valid)
 	if err != nil {
@@ -222,6 +367,27 @@ func MustNewUtf8Column(name string, data []string, valid []bool) *Utf8Column {
 	}
 	return c
 }
+func MustNewDateColumn(name string, data []time.Time, valid []bool) *DateColumn {
+	c, err := NewDateColumn(name, data, valid)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+func MustNewDatetimeColumn(name string, unit TimeUnit, tz string, data []time.Time, valid []bool) *DatetimeColumn {
+	c, err := NewDatetimeColumn(name, unit, tz, data, valid)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+func MustNewDurationColumn(name string, unit TimeUnit, data []time.Duration, valid []bool) *DurationColumn {
+	c, err := NewDurationColumn(name, unit, data, valid)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
 
 type DataFrame struct {
 	df *exec.DataFrame
@@ -343,7 +509,9 @@ func (df *DataFrame) Rename(oldName, newName string) (*DataFrame, error) {
 	return &DataFrame{df: out}, nil
 }
 
-func (df *DataFrame) WithColumns(cols ...Column) (*DataFrame, error) {
+// WithColumns adds or replaces columns. Expressions are evaluated against df
+// as it was before the call, so they cannot see each other's results.
+func (df *DataFrame) WithColumns(cols ...IntoColumn) (*DataFrame, error) {
 	if len(cols) == 0 {
 		return df, nil
 	}
@@ -352,10 +520,14 @@ func (df *DataFrame) WithColumns(cols ...Column) (*DataFrame, error) {
 		if cols[i] == nil {
 			return nil, fmt.Errorf("nil column")
 		}
-		internal[i] = cols[i].internalColumn()
-		if internal[i] == nil {
+		col, err := cols[i].intoColumn(df.df)
+		if err != nil {
+			return nil, err
+		}
+		if col == nil {
 			return nil, fmt.Errorf("nil column")
 		}
+		internal[i] = col
 	}
 	out, err := df.df.WithColumns(internal...)
 	if err != nil {
@@ -371,18 +543,42 @@ type GroupBy struct {
 type AggFunc = exec.AggFunc
 
 const (
-	AggInvalid = exec.AggInvalid
-	AggCount   = exec.AggCount
-	AggSum     = exec.AggSum
-	AggMean    = exec.AggMean
-	AggMin     = exec.AggMin
-	AggMax     = exec.AggMax
+	AggInvalid   = exec.AggInvalid
+	AggCount     = exec.AggCount
+	AggSum       = exec.AggSum
+	AggMean      = exec.AggMean
+	AggMin       = exec.AggMin
+	AggMax       = exec.AggMax
+	AggMedian    = exec.AggMedian
+	AggQuantile  = exec.AggQuantile
+	AggStd       = exec.AggStd
+	AggVar       = exec.AggVar
+	AggNUnique   = exec.AggNUnique
+	AggFirst     = exec.AggFirst
+	AggLast      = exec.AggLast
+	AggNullCount = exec.AggNullCount
+)
+
+type QuantileInterpolation = exec.QuantileInterpolation
+
+const (
+	QuantileNearest  = exec.QuantileNearest
+	QuantileLower    = exec.QuantileLower
+	QuantileHigher   = exec.QuantileHigher
+	QuantileMidpoint = exec.QuantileMidpoint
+	QuantileLinear   = exec.QuantileLinear
 )
 
 type Agg struct {
 	Col   string
 	Func  AggFunc
 	Alias string
+	// Quantile and Interpolation are used by AggQuantile.
+	Quantile      float64
+	Interpolation QuantileInterpolation
+	// DDof is the delta degrees of freedom used by AggStd and AggVar; nil
+	// means 1, the sample estimator. Set it with WithDDof.
+	DDof *int
 }
 
 func (a Agg) As(alias string) Agg {
@@ -396,6 +592,40 @@ func Mean(col string) Agg { return Agg{Func: AggMean, Col: col} }
 func Min(col string) Agg  { return Agg{Func: AggMin, Col: col} }
 func Max(col string) Agg  { return Agg{Func: AggMax, Col: col} }
 
+// WithDDof overrides the delta degrees of freedom of a Std or Var aggregation.
+func (a Agg) WithDDof(ddof int) Agg {
+	a.DDof = &ddof
+	return a
+}
+
+func Median(col string) Agg { return Agg{Func: AggMedian, Col: col} }
+
+// Quantile returns the q-th quantile (0 <= q <= 1) of col per group.
+func Quantile(col string, q float64, interp QuantileInterpolation) Agg {
+	return Agg{Func: AggQuantile, Col: col, Quantile: q, Interpolation: interp}
+}
+
+// Std and Var use the sample estimator (ddof=1) unless WithDDof says
+// otherwise.
+func Std(col string) Agg { return Agg{Func: AggStd, Col: col} }
+func Var(col string) Agg { return Agg{Func: AggVar, Col: col} }
+
+func NUnique(col string) Agg   { return Agg{Func: AggNUnique, Col: col} }
+func First(col string) Agg     { return Agg{Func: AggFirst, Col: col} }
+func Last(col string) Agg      { return Agg{Func: AggLast, Col: col} }
+func NullCount(col string) Agg { return Agg{Func: AggNullCount, Col: col} }
+
+func (a Agg) internal() exec.AggSpec {
+	return exec.AggSpec{
+		Col:           a.Col,
+		Func:          a.Func,
+		Alias:         a.Alias,
+		Quantile:      a.Quantile,
+		Interpolation: a.Interpolation,
+		DDof:          a.DDof,
+	}
+}
+
 func (df *DataFrame) GroupBy(keys ...string) (*GroupBy, error) {
 	gb, err := df.df.GroupBy(keys...)
 	if err != nil {
@@ -418,7 +648,7 @@ func (g *GroupBy) Agg(specs ...Agg) (*DataFrame, error) {
 	}
 	internal := make([]exec.AggSpec, len(specs))
 	for i := range specs {
-		internal[i] = exec.AggSpec{Col: specs[i].Col, Func: specs[i].Func, Alias: specs[i].Alias}
+		internal[i] = specs[i].internal()
 	}
 	df, err := g.gb.Agg(internal...)
 	if err != nil {
@@ -427,6 +657,78 @@ func (g *GroupBy) Agg(specs ...Agg) (*DataFrame, error) {
 	return &DataFrame{df: df}, nil
 }
 
+type JoinType = exec.JoinType
+
+const (
+	JoinInner = exec.JoinInner
+	JoinLeft  = exec.JoinLeft
+	JoinRight = exec.JoinRight
+	JoinFull  = exec.JoinFull
+	JoinSemi  = exec.JoinSemi
+	JoinAnti  = exec.JoinAnti
+)
+
+// JoinOptions configures DataFrame.Join. Set On when both frames share key
+// names, or LeftOn and RightOn otherwise.
+type JoinOptions = exec.JoinOptions
+
+// Join combines df with other on equal key values. See JoinOptions for the
+// supported join types, key naming and NULL matching.
+func (df *DataFrame) Join(other *DataFrame, opts JoinOptions) (*DataFrame, error) {
+	if other == nil {
+		return nil, fmt.Errorf("join: nil dataframe")
+	}
+	out, err := df.df.Join(other.df, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &DataFrame{df: out}, nil
+}
+
+type AsofStrategy = exec.AsofStrategy
+
+const (
+	AsofBackward = exec.AsofBackward
+	AsofForward  = exec.AsofForward
+	AsofNearest  = exec.AsofNearest
+)
+
+// AsofOptions configures DataFrame.JoinAsof.
+type AsofOptions = exec.AsofOptions
+
+// JoinAsof matches each left row with the nearest right row by a sorted key,
+// e.g. the latest quote at or before each trade. See AsofOptions.
+func (df *DataFrame) JoinAsof(other *DataFrame, opts AsofOptions) (*DataFrame, error) {
+	if other == nil {
+		return nil, fmt.Errorf("asof: nil dataframe")
+	}
+	out, err := df.df.JoinAsof(other.df, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &DataFrame{df: out}, nil
+}
+
+// TopK returns the k rows that SortBy would put first, ordered, without a
+// full sort; by lists the sort columns in priority order. With desc the k
+// largest rows come first, e.g. the worst 50 latencies. NULLs rank last.
+func (df *DataFrame) TopK(k int, by []string, desc bool) (*DataFrame, error) {
+	out, err := df.df.TopK(k, by, desc)
+	if err != nil {
+		return nil, err
+	}
+	return &DataFrame{df: out}, nil
+}
+
+// BottomK returns the k rows from the opposite end of the order TopK uses.
+func (df *DataFrame) BottomK(k int, by []string, desc bool) (*DataFrame, error) {
+	out, err := df.df.BottomK(k, by, desc)
+	if err != nil {
+		return nil, err
+	}
+	return &DataFrame{df: out}, nil
+}
+
 func (df *DataFrame) SortBy(column string, desc bool) (*DataFrame, error) {
 	out, err := df.df.SortBy(column, desc)
 	if err != nil {
@@ -435,14 +737,64 @@ func (df *DataFrame) SortBy(column string, desc bool) (*DataFrame, error) {
 	return &DataFrame{df: out}, nil
 }
 
+// SortKey is one sort column with its direction and NULL placement; NULLs
+// come last unless NullsFirst is set.
+type SortKey = exec.SortKey
+
+// SortOptions controls a multi-column sort. Set MaintainOrder to keep rows
+// with equal keys in input order.
+type SortOptions = exec.SortOptions
+
+// SortKeys orders rows by keys in priority order, e.g. region ascending and
+// then revenue descending with NULL revenue first.
+func (df *DataFrame) SortKeys(keys []SortKey, opts SortOptions) (*DataFrame, error) {
+	out, err := df.df.SortKeys(keys, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &DataFrame{df: out}, nil
+}
+
 func (df *DataFrame) ProjectionChecksum(maxCols int) string {
 	return df.df.ProjectionChecksum(maxCols)
 }
 
+type CSVWriteOptions = csvio.WriteOptions
+type QuoteStyle = csvio.QuoteStyle
+
+const (
+	QuoteNecessary  = csvio.QuoteNecessary
+	QuoteAlways     = csvio.QuoteAlways
+	QuoteNonNumeric = csvio.QuoteNonNumeric
+	QuoteNever      = csvio.QuoteNever
+)
+
+// WriteCSV streams df to w as CSV with a header row unless opts.NoHeader is
+// set. Chunks of rows are formatted in parallel and written in order, so
+// the full output is never held in memory.
+func (df *DataFrame) WriteCSV(w io.Writer, opts CSVWriteOptions) error {
+	return csvio.Write(w, df.df, opts)
+}
+
 func (df *DataFrame) MarshalRowsJSON() ([]byte, error) {
 	return df.df.MarshalRowsJSON()
 }
 
+// JSONWriteOptions configure WriteJSON and WriteNDJSON; set Parallel to
+// encode row ranges on several workers.
+type JSONWriteOptions = exec.JSONWriteOptions
+
+// WriteJSON streams df to w as a JSON array of row objects, producing the
+// same bytes as MarshalRowsJSON without building them in one buffer.
+func (df *DataFrame) WriteJSON(w io.Writer, opts JSONWriteOptions) error {
+	return df.df.WriteJSON(w, opts)
+}
+
+// WriteNDJSON streams df to w as newline-delimited JSON, one object per row.
+func (df *DataFrame) WriteNDJSON(w io.Writer, opts JSONWriteOptions) error {
+	return df.df.WriteNDJSON(w, opts)
+}
+
 type ScanOptions = plan.ScanOptions
 
 type LazyFrame struct {
@@ -452,7 +804,27 @@ type LazyFrame struct {
 func ScanCSV(path string, opts ScanOptions) *LazyFrame {
 	return &LazyFrame{lf: plan.ScanCSV(path, opts)}
 }
-func ScanJSON(path string) *LazyFrame { return &LazyFrame{lf: plan.ScanJSON(path)} }
+
+// ScanJSON scans a JSON document: the elements of its root array.
+func ScanJSON(path string) *LazyFrame {
+	return ScanJSONWith(path, JSONScanOptions{})
+}
+
+// ScanJSONWith is ScanJSON with options, such as a RecordPath selecting the
+// records inside the document.
+func ScanJSONWith(path string, opts JSONScanOptions) *LazyFrame {
+	return &LazyFrame{lf: plan.ScanJSON(path, opts)}
+}
+
+// JSONScanOptions configure ScanJSONWith and ScanNDJSON; see
+// plan.JSONScanOptions.
+type JSONScanOptions = plan.JSONScanOptions
+
+// ScanNDJSON scans newline-delimited JSON (JSON Lines), parsing line-aligned
+// blocks in parallel. The schema is inferred from the first records.
+func ScanNDJSON(path string, opts JSONScanOptions) *LazyFrame {
+	return &LazyFrame{lf: plan.ScanNDJSON(path, opts)}
+}
 
 func (lf *LazyFrame) Select(cols ...string) *LazyFrame {
 	return &LazyFrame{lf: lf.lf.Select(cols...)}
@@ -463,6 +835,53 @@ func (lf *LazyFrame) Filter(e Expr) *LazyFrame {
 func (lf *LazyFrame) Sort(col string, desc bool) *LazyFrame {
 	return &LazyFrame{lf: lf.lf.Sort(col, desc)}
 }
+
+// SortKeys is the lazy form of DataFrame.SortKeys.
+func (lf *LazyFrame) SortKeys(keys []SortKey, opts SortOptions) *LazyFrame {
+	return &LazyFrame{lf: lf.lf.SortKeys(keys, opts)}
+}
+
+// Slice keeps length rows starting at offset. When no sort, GroupBy or join
+// precedes it, the scan stops reading once enough rows passed the filters;
+// a Sort directly before it runs as a parallel top-k.
+func (lf *LazyFrame) Slice(offset, length int) *LazyFrame {
+	return &LazyFrame{lf: lf.lf.Slice(offset, length)}
+}
+func (lf *LazyFrame) Head(n int) *LazyFrame  { return &LazyFrame{lf: lf.lf.Head(n)} }
+func (lf *LazyFrame) Limit(n int) *LazyFrame { return &LazyFrame{lf: lf.lf.Limit(n)} }
+
+// WithColumns adds or replaces columns computed from value expressions.
+func (lf *LazyFrame) WithColumns(vals ...ValueExpr) *LazyFrame {
+	internal := make([]expr.Value, len(vals))
+	for i := range vals {
+		internal[i] = vals[i].v
+	}
+	return &LazyFrame{lf: lf.lf.WithColumns(internal...)}
+}
+
+// Join adds a join with another lazy plan. Filters and projections that only
+// concern one side are pushed into that side's scan.
+func (lf *LazyFrame) Join(other *LazyFrame, opts JoinOptions) *LazyFrame {
+	return &LazyFrame{lf: lf.lf.Join(other.lf, opts)}
+}
+
+// LazyGroupBy is a pending lazy GroupBy; call Agg or Count to add the node.
+type LazyGroupBy struct {
+	g *plan.LazyGroupBy
+}
+
+func (lf *LazyFrame) GroupBy(keys ...string) *LazyGroupBy {
+	return &LazyGroupBy{g: lf.lf.GroupBy(keys...)}
+}
+func (g *LazyGroupBy) Agg(specs ...Agg) *LazyFrame {
+	internal := make([]exec.AggSpec, len(specs))
+	for i := range specs {
+		internal[i] = specs[i].internal()
+	}
+	return &LazyFrame{lf: g.g.Agg(internal...)}
+}
+func (g *LazyGroupBy) Count() *LazyFrame { return g.Agg(Count()) }
+
 func (lf *LazyFrame) Collect() (*DataFrame, error) {
 	return lf.CollectContext(context.Background())
 }
@@ -493,12 +912,14 @@ type ColRef struct{ name string }
 
 func Col(name string) ColRef { return ColRef{name: name} }
 
-func (c ColRef) Eq(v any) Expr  { return wrappedExpr{e: expr.Col(c.name).Eq(v)} }
-func (c ColRef) Neq(v any) Expr { return wrappedExpr{e: expr.Col(c.name).Neq(v)} }
-func (c ColRef) Lt(v any) Expr  { return wrappedExpr{e: expr.Col(c.name).Lt(v)} }
-func (c ColRef) Lte(v any) Expr { return wrappedExpr{e: expr.Col(c.name).Lte(v)} }
-func (c ColRef) Gt(v any) Expr  { return wrappedExpr{e: expr.Col(c.name).Gt(v)} }
-func (c ColRef) Gte(v any) Expr { return wrappedExpr{e: expr.Col(c.name).Gte(v)} }
+// Eq, Neq, Lt, Lte, Gt and Gte compare the column with a literal, another
+// ColRef or a ValueExpr. Int64 and float64 sides are compared as float64.
+func (c ColRef) Eq(v any) Expr  { return wrappedExpr{e: expr.Col(c.name).Eq(operand(v))} }
+func (c ColRef) Neq(v any) Expr { return wrappedExpr{e: expr.Col(c.name).Neq(operand(v))} }
+func (c ColRef) Lt(v any) Expr  { return wrappedExpr{e: expr.Col(c.name).Lt(operand(v))} }
+func (c ColRef) Lte(v any) Expr { return wrappedExpr{e: expr.Col(c.name).Lte(operand(v))} }
+func (c ColRef) Gt(v any) Expr  { return wrappedExpr{e: expr.Col(c.name).Gt(operand(v))} }
+func (c ColRef) Gte(v any) Expr { return wrappedExpr{e: expr.Col(c.name).Gte(operand(v))} }
 func (c ColRef) Even() Expr     { return wrappedExpr{e: expr.Col(c.name).Even()} }
 func (c ColRef) IsNull() Expr   { return wrappedExpr{e: expr.Col(c.name).IsNull()} }
 func (c ColRef) IsNotNull() Expr {
@@ -506,6 +927,227 @@ func (c ColRef) IsNotNull() Expr {
 }
 func (c ColRef) In(vals ...any) Expr { return wrappedExpr{e: expr.Col(c.name).In(vals...)} }
 
+// ValueExpr is an expression that computes a column, such as
+// Col("a").Add(Col("b")). Int64 op int64 stays int64 except Div, which
+// produces float64; any float64 operand makes the result float64. NULL on
+// either side gives NULL, as does integer Mod by zero.
+type ValueExpr struct{ v expr.Value }
+
+func (e ValueExpr) intoColumn(df *exec.DataFrame) (array.Column, error) {
+	if e.v == nil {
+		return nil, fmt.Errorf("nil expression")
+	}
+	return df.EvalValue(e.v)
+}
+
+// Lit is a constant broadcast to every row.
+func Lit(v any) ValueExpr { return ValueExpr{v: expr.Lit(v)} }
+
+// toValue accepts a ValueExpr, a ColRef or a Go literal.
+func toValue(v any) expr.Value {
+	switch x := v.(type) {
+	case ValueExpr:
+		return x.v
+	case ColRef:
+		return expr.Col(x.name)
+	default:
+		return expr.Lit(v)
+	}
+}
+
+// operand converts ColRef and ValueExpr comparison operands to internal
+// values and passes literals through unchanged.
+func operand(v any) any {
+	switch v.(type) {
+	case ValueExpr, ColRef:
+		return toValue(v)
+	default:
+		return v
+	}
+}
+
+func (e ValueExpr) Add(v any) ValueExpr { return ValueExpr{v: expr.Add(e.v, toValue(v))} }
+func (e ValueExpr) Sub(v any) ValueExpr { return ValueExpr{v: expr.Sub(e.v, toValue(v))} }
+func (e ValueExpr) Mul(v any) ValueExpr { return ValueExpr{v: expr.Mul(e.v, toValue(v))} }
+func (e ValueExpr) Div(v any) ValueExpr { return ValueExpr{v: expr.Div(e.v, toValue(v))} }
+func (e ValueExpr) Mod(v any) ValueExpr { return ValueExpr{v: expr.Mod(e.v, toValue(v))} }
+func (e ValueExpr) Neg() ValueExpr      { return ValueExpr{v: expr.Neg(e.v)} }
+
+func (e ValueExpr) Eq(v any) Expr  { return wrappedExpr{e: expr.Eq(e.v, toValue(v))} }
+func (e ValueExpr) Neq(v any) Expr { return wrappedExpr{e: expr.Neq(e.v, toValue(v))} }
+func (e ValueExpr) Lt(v any) Expr  { return wrappedExpr{e: expr.Lt(e.v, toValue(v))} }
+func (e ValueExpr) Lte(v any) Expr { return wrappedExpr{e: expr.Lte(e.v, toValue(v))} }
+func (e ValueExpr) Gt(v any) Expr  { return wrappedExpr{e: expr.Gt(e.v, toValue(v))} }
+func (e ValueExpr) Gte(v any) Expr { return wrappedExpr{e: expr.Gte(e.v, toValue(v))} }
+
+// Cast converts to dtype. In lenient mode values that do not convert, such
+// as unparsable strings or out-of-range numbers, become NULL; with strict
+// the first such value fails evaluation with its row.
+func (e ValueExpr) Cast(dtype DataType, strict bool) ValueExpr {
+	return ValueExpr{v: expr.Cast(e.v, dtype, strict)}
+}
+
+// Alias names the output column. Without it, the name is that of the
+// leftmost column reference.
+func (e ValueExpr) Alias(name string) ValueExpr { return ValueExpr{v: expr.Alias(e.v, name)} }
+
+func (c ColRef) value() ValueExpr                           { return ValueExpr{v: expr.Col(c.name)} }
+func (c ColRef) Add(v any) ValueExpr                        { return c.value().Add(v) }
+func (c ColRef) Sub(v any) ValueExpr                        { return c.value().Sub(v) }
+func (c ColRef) Mul(v any) ValueExpr                        { return c.value().Mul(v) }
+func (c ColRef) Div(v any) ValueExpr                        { return c.value().Div(v) }
+func (c ColRef) Mod(v any) ValueExpr                        { return c.value().Mod(v) }
+func (c ColRef) Neg() ValueExpr                             { return c.value().Neg() }
+func (c ColRef) Cast(dtype DataType, strict bool) ValueExpr { return c.value().Cast(dtype, strict) }
+func (c ColRef) Alias(name string) ValueExpr                { return c.value().Alias(name) }
+func (c ColRef) intoColumn(df *exec.DataFrame) (array.Column, error) {
+	return c.value().intoColumn(df)
+}
+
+// When starts a conditional expression:
+//
+//	When(Col("qty").Gt(10)).Then("bulk").When(Col("qty").Gt(0)).Then("retail").Otherwise("none")
+//
+// The first branch whose condition holds wins; a NULL condition counts as
+// false. Then and Otherwise take ColRefs, ValueExprs or literals.
+func When(cond Expr) WhenClause { return WhenClause{cond: cond.internal()} }
+
+type WhenClause struct {
+	prev *WhenThen
+	cond expr.Expr
+}
+
+type WhenThen struct {
+	conds []expr.Expr
+	thens []expr.Value
+}
+
+func (w WhenClause) Then(v any) WhenThen {
+	var out WhenThen
+	if w.prev != nil {
+		out.conds = append([]expr.Expr(nil), w.prev.conds...)
+		out.thens = append([]expr.Value(nil), w.prev.thens...)
+	}
+	out.conds = append(out.conds, w.cond)
+	out.thens = append(out.thens, toValue(v))
+	return out
+}
+
+func (w WhenThen) When(cond Expr) WhenClause { return WhenClause{prev: &w, cond: cond.internal()} }
+
+// Otherwise finishes the chain; Otherwise(nil) leaves unmatched rows NULL.
+func (w WhenThen) Otherwise(v any) ValueExpr {
+	var otherwise expr.Value
+	if v != nil {
+		otherwise = toValue(v)
+	}
+	return ValueExpr{v: expr.When(w.conds, w.thens, otherwise)}
+}
+
+// Coalesce takes the first non-NULL value of its arguments in every row.
+func Coalesce(vals ...any) ValueExpr {
+	internal := make([]expr.Value, len(vals))
+	for i := range vals {
+		internal[i] = toValue(vals[i])
+	}
+	return ValueExpr{v: expr.Coalesce(internal...)}
+}
+
+type FillStrategy = expr.FillStrategy
+
+const (
+	FillForward  = expr.FillForward
+	FillBackward = expr.FillBackward
+	FillMean     = expr.FillMean
+)
+
+// FillNull replaces NULLs with a literal, ColRef or ValueExpr.
+func (e ValueExpr) FillNull(v any) ValueExpr {
+	return ValueExpr{v: expr.FillNull(e.v, toValue(v))}
+}
+
+// FillNullStrategy replaces NULLs from the column's own values.
+func (e ValueExpr) FillNullStrategy(s FillStrategy) ValueExpr {
+	return ValueExpr{v: expr.FillNullStrategy(e.v, s)}
+}
+
+func (c ColRef) FillNull(v any) ValueExpr { return c.value().FillNull(v) }
+func (c ColRef) FillNullStrategy(s FillStrategy) ValueExpr {
+	return c.value().FillNullStrategy(s)
+}
+
+// StrNamespace holds string functions on a utf8 expression; obtain it with
+// ColRef.Str or ValueExpr.Str.
+type StrNamespace struct{ v expr.Value }
+
+func (e ValueExpr) Str() StrNamespace { return StrNamespace{v: e.v} }
+func (c ColRef) Str() StrNamespace    { return StrNamespace{v: expr.Col(c.name)} }
+
+func (s StrNamespace) Contains(sub string) Expr {
+	return wrappedExpr{e: expr.StrContains(s.v, sub)}
+}
+func (s StrNamespace) StartsWith(prefix string) Expr {
+	return wrappedExpr{e: expr.StrStartsWith(s.v, prefix)}
+}
+func (s StrNamespace) EndsWith(suffix string) Expr {
+	return wrappedExpr{e: expr.StrEndsWith(s.v, suffix)}
+}
+
+// Matches tests values against a regular expression in RE2 syntax.
+func (s StrNamespace) Matches(pattern string) Expr {
+	return wrappedExpr{e: expr.StrMatches(s.v, pattern)}
+}
+
+// LenBytes and LenChars return int64 lengths in bytes and code points.
+func (s StrNamespace) LenBytes() ValueExpr { return ValueExpr{v: expr.StrLen(s.v, false)} }
+func (s StrNamespace) LenChars() ValueExpr { return ValueExpr{v: expr.StrLen(s.v, true)} }
+
+func (s StrNamespace) Lower() ValueExpr { return ValueExpr{v: expr.StrLower(s.v)} }
+func (s StrNamespace) Upper() ValueExpr { return ValueExpr{v: expr.StrUpper(s.v)} }
+
+// Strip trims leading and trailing characters in chars, or white space when
+// chars is empty.
+func (s StrNamespace) Strip(chars string) ValueExpr {
+	return ValueExpr{v: expr.StrStrip(s.v, chars)}
+}
+
+// Slice takes length characters from offset; a negative offset counts from
+// the end and a negative length runs to the end.
+func (s StrNamespace) Slice(offset, length int) ValueExpr {
+	return ValueExpr{v: expr.StrSlice(s.v, offset, length)}
+}
+
+// Replace replaces every occurrence of old.
+func (s StrNamespace) Replace(old, with string) ValueExpr {
+	return ValueExpr{v: expr.StrReplace(s.v, old, with)}
+}
+
+// Split splits on sep into n columns named <name>_0 .. <name>_<n-1>. Missing
+// parts are NULL; the last column does not absorb any remainder. n <= 0
+// gives no columns.
+func (s StrNamespace) Split(sep string, n int) []ValueExpr {
+	if n <= 0 {
+		return nil
+	}
+	name := expr.OutputName(s.v)
+	out := make([]ValueExpr, n)
+	for i := range out {
+		out[i] = ValueExpr{v: expr.Alias(expr.StrSplitPart(s.v, sep, i), fmt.Sprintf("%s_%d", name, i))}
+	}
+	return out
+}
+
+// ConcatStr joins values row by row with sep. Arguments may be ColRefs,
+// ValueExprs or literals; non-utf8 values are formatted. A NULL in any
+// argument gives NULL.
+func ConcatStr(sep string, vals ...any) ValueExpr {
+	internal := make([]expr.Value, len(vals))
+	for i := range vals {
+		internal[i] = toValue(vals[i])
+	}
+	return ValueExpr{v: expr.ConcatStr(sep, internal...)}
+}
+
 func Not(e Expr) Expr { return wrappedExpr{e: expr.Not(e.internal())} }
 
 func And(a, b Expr) Expr { return wrappedExpr{e: expr.And(a.internal(), b.internal())} }
@@ -0,0 +1,233 @@
+package grizzly
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestGroupByMultiKeyUtf8(t *testing.T) {
+	country, err := NewUtf8Column("country", []string{"de", "us", "de", "", "us", "de"}, []bool{true, true, true, false, true, true})
+	if err != nil {
+		t.Fatalf("new column: %v", err)
+	}
+	vip := MustNewBoolColumn("vip", []bool{true, false, true, false, false, false}, nil)
+	amount := MustNewFloat64Column("amount", []float64{1, 2, 3, 4, 5, 6}, nil)
+	df, err := NewDataFrame(country, vip, amount)
+	if err != nil {
+		t.Fatalf("new dataframe: %v", err)
+	}
+	gb, err := df.GroupBy("country", "vip")
+	if err != nil {
+		t.Fatalf("groupby: %v", err)
+	}
+	out, err := gb.Agg(Count(), Sum("amount"))
+	if err != nil {
+		t.Fatalf("agg: %v", err)
+	}
+	if out.Height() != 4 {
+		t.Fatalf("expected 4 groups got %d", out.Height())
+	}
+	cs, _ := out.Column("country")
+	cc, _ := cs.Utf8()
+	vs, _ := out.Column("vip")
+	vc, _ := vs.Bool()
+	ns, _ := out.Column("count")
+	nc, _ := ns.Int64()
+	ss, _ := out.Column("amount_sum")
+	sc, _ := ss.Float64()
+
+	// First-seen order: (de,true) (us,false) (null,false) (de,false).
+	type group struct {
+		country string
+		null    bool
+		vip     bool
+		count   int64
+		sum     float64
+	}
+	want := []group{
+		{"de", false, true, 2, 4},
+		{"us", false, false, 2, 7},
+		{"", true, false, 1, 4},
+		{"de", false, false, 1, 6},
+	}
+	for i, w := range want {
+		if cc.IsNull(i) != w.null || (!w.null && cc.Value(i) != w.country) {
+			t.Fatalf("group %d: unexpected country", i)
+		}
+		if vc.Value(i) != w.vip || nc.Value(i) != w.count || sc.Value(i) != w.sum {
+			t.Fatalf("group %d: got vip=%v count=%d sum=%v", i, vc.Value(i), nc.Value(i), sc.Value(i))
+		}
+	}
+}
+
+func TestGroupByStatAggs(t *testing.T) {
+	key := MustNewInt64Column("k", []int64{1, 1, 1, 1, 2, 2}, nil)
+	val := MustNewInt64Column("v", []int64{4, 1, 3, 2, 10, 0}, []bool{true, true, true, true, true, false})
+	name := MustNewUtf8Column("name", []string{"b", "a", "c", "a", "", "z"}, []bool{true, true, true, true, false, true})
+	df, err := NewDataFrame(key, val, name)
+	if err != nil {
+		t.Fatalf("new dataframe: %v", err)
+	}
+	gb, err := df.GroupBy("k")
+	if err != nil {
+		t.Fatalf("groupby: %v", err)
+	}
+	out, err := gb.Agg(
+		Median("v"),
+		Quantile("v", 0.25, QuantileLower).As("q25"),
+		Var("v"),
+		Std("v").WithDDof(0).As("pstd"),
+		Agg{Func: AggStd, Col: "v", Alias: "lit_std"},
+		NUnique("name"),
+		First("name"),
+		Last("v"),
+		NullCount("v"),
+		Max("name"),
+	)
+	if err != nil {
+		t.Fatalf("agg: %v", err)
+	}
+	f64 := func(col string, i int) (float64, bool) {
+		s, _ := out.Column(col)
+		c, ok := s.Float64()
+		if !ok {
+			t.Fatalf("%s: expected float64", col)
+		}
+		return c.Value(i), c.IsNull(i)
+	}
+	if v, _ := f64("v_median", 0); v != 2.5 {
+		t.Fatalf("median got %v", v)
+	}
+	if v, _ := f64("q25", 0); v != 1 {
+		t.Fatalf("q25 got %v", v)
+	}
+	if v, _ := f64("v_var", 0); math.Abs(v-5.0/3) > 1e-12 {
+		t.Fatalf("var got %v", v)
+	}
+	if v, _ := f64("pstd", 0); math.Abs(v-math.Sqrt(1.25)) > 1e-12 {
+		t.Fatalf("pstd got %v", v)
+	}
+	if v, _ := f64("lit_std", 0); math.Abs(v-math.Sqrt(5.0/3)) > 1e-12 {
+		t.Fatalf("struct literal std should be the sample std, got %v", v)
+	}
+	if _, null := f64("v_var", 1); !null {
+		t.Fatalf("var of a single value should be NULL")
+	}
+
+	s, _ := out.Column("name_n_unique")
+	if got := s.ValueString(0) + "," + s.ValueString(1); got != "3,2" {
+		t.Fatalf("n_unique got %s", got)
+	}
+	s, _ = out.Column("name_first")
+	if s.ValueString(0) != "b" || !s.IsNull(1) {
+		t.Fatalf("first got %s/%s", s.ValueString(0), s.ValueString(1))
+	}
+	s, _ = out.Column("v_last")
+	if s.ValueString(0) != "2" || !s.IsNull(1) {
+		t.Fatalf("last got %s/%s", s.ValueString(0), s.ValueString(1))
+	}
+	s, _ = out.Column("v_null_count")
+	if s.ValueString(0) != "0" || s.ValueString(1) != "1" {
+		t.Fatalf("null_count got %s/%s", s.ValueString(0), s.ValueString(1))
+	}
+	s, _ = out.Column("name_max")
+	if s.ValueString(0) != "c" || s.ValueString(1) != "z" {
+		t.Fatalf("max got %s/%s", s.ValueString(0), s.ValueString(1))
+	}
+
+	for _, q := range []float64{-0.1, 1.5, math.NaN()} {
+		if _, err := gb.Agg(Quantile("v", q, QuantileLinear)); err == nil || !strings.Contains(err.Error(), "quantile must be in [0, 1]") {
+			t.Fatalf("quantile %v: expected a range error, got %v", q, err)
+		}
+	}
+}
+
+func TestGroupByParallelMatchesSequentialSemantics(t *testing.T) {
+	const n = 250000
+	keys := make([]int64, n)
+	keyValid := make([]bool, n)
+	vals := make([]float64, n)
+	valValid := make([]bool, n)
+	for i := range keys {
+		keys[i] = int64((i * 7919) % 1013)
+		keyValid[i] = i%997 != 0
+		vals[i] = float64(i % 101)
+		valValid[i] = i%13 != 0
+	}
+	df, err := NewDataFrame(
+		MustNewInt64Column("k", keys, keyValid),
+		MustNewFloat64Column("v", vals, valValid),
+	)
+	if err != nil {
+		t.Fatalf("new dataframe: %v", err)
+	}
+
+	// Reference aggregation in a single pass.
+	type ref struct {
+		first, last int
+		count       int64
+		sum         float64
+		nulls       int64
+	}
+	var order []int64
+	byKey := map[int64]*ref{}
+	for i := range keys {
+		k := keys[i]
+		if !keyValid[i] {
+			k = -1
+		}
+		r, ok := byKey[k]
+		if !ok {
+			r = &ref{first: i}
+			byKey[k] = r
+			order = append(order, k)
+		}
+		r.last = i
+		r.count++
+		if valValid[i] {
+			r.sum += vals[i]
+		} else {
+			r.nulls++
+		}
+	}
+
+	gb, err := df.GroupBy("k")
+	if err != nil {
+		t.Fatalf("groupby: %v", err)
+	}
+	out, err := gb.Agg(Count(), Sum("v"), First("v"), Last("v"), NullCount("v"))
+	if err != nil {
+		t.Fatalf("agg: %v", err)
+	}
+	if out.Height() != len(order) {
+		t.Fatalf("expected %d groups got %d", len(order), out.Height())
+	}
+	ks, _ := out.Column("k")
+	kc, _ := ks.Int64()
+	cs, _ := out.Column("count")
+	cc, _ := cs.Int64()
+	ss, _ := out.Column("v_sum")
+	sc, _ := ss.Float64()
+	fs, _ := out.Column("v_first")
+	fc, _ := fs.Float64()
+	ls, _ := out.Column("v_last")
+	lc, _ := ls.Float64()
+	ns, _ := out.Column("v_null_count")
+	nc, _ := ns.Int64()
+	for i, k := range order {
+		r := byKey[k]
+		if (k == -1) != kc.IsNull(i) || (k != -1 && kc.Value(i) != k) {
+			t.Fatalf("group %d: unexpected key", i)
+		}
+		if cc.Value(i) != r.count || nc.Value(i) != r.nulls || math.Abs(sc.Value(i)-r.sum) > 1e-6 {
+			t.Fatalf("group %d: got count=%d nulls=%d sum=%v", i, cc.Value(i), nc.Value(i), sc.Value(i))
+		}
+		if fc.IsNull(i) != !valValid[r.first] || (valValid[r.first] && fc.Value(i) != vals[r.first]) {
+			t.Fatalf("group %d: unexpected first", i)
+		}
+		if lc.IsNull(i) != !valValid[r.last] || (valValid[r.last] && lc.Value(i) != vals[r.last]) {
+			t.Fatalf("group %d: unexpected last", i)
+		}
+	}
+}
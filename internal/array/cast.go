@@ -0,0 +1,248 @@
+package array
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Cast converts col to the target type. Values that cannot be represented
+// in the target type (unparsable strings, NaN, values outside an integer
+// type's or float32's range) become NULL. Casting to the column's own type returns col unchanged.
+func Cast(col Column, to DataType) (Column, error) { return cast(col, to, false) }
+
+// CastStrict is Cast that fails on the first non-NULL value that does not
+// convert, reporting its row, instead of turning it into NULL.
+func CastStrict(col Column, to DataType) (Column, error) { return cast(col, to, true) }
+
+func cast(col Column, to DataType, strict bool) (Column, error) {
+	if col.DType() == to {
+		return col, nil
+	}
+	n := col.Len()
+	switch to.Kind {
+	case KindInt, KindUInt, KindFloat:
+		out, err := castNumeric(col, to, strict)
+		if out != nil || err != nil {
+			return out, err
+		}
+	case KindBool:
+		if get, ok := castSourceBool(col); ok {
+			return castRows(col, to, strict, NewBoolColumnOwned, get)
+		}
+	case KindUtf8:
+		offsets := make([]int32, 1, n+1)
+		buf := make([]byte, 0, n*8)
+		valid := BitmapBuilder{}
+		valid.Reserve(n)
+		for i := 0; i < n; i++ {
+			if !col.IsNull(i) {
+				buf = append(buf, col.ValueString(i)...)
+			}
+			offsets = append(offsets, int32(len(buf)))
+			valid.Append(!col.IsNull(i))
+		}
+		return NewUtf8ColumnOwned(col.Name(), offsets, buf, valid.Build()), nil
+	}
+	return nil, fmt.Errorf("cannot cast %s to %s", col.DType(), to)
+}
+
+// castNumeric converts col to the numeric type to. It returns a nil column
+// and error when no conversion from col's type exists.
+func castNumeric(col Column, to DataType, strict bool) (Column, error) {
+	switch to {
+	case Int(64):
+		return castInts(col, to, strict, math.MinInt64, math.MaxInt64, NewInt64ColumnOwned)
+	case Int(32):
+		return castInts(col, to, strict, math.MinInt32, math.MaxInt32, NewInt32ColumnOwned)
+	case Int(16):
+		return castInts(col, to, strict, math.MinInt16, math.MaxInt16, NewInt16ColumnOwned)
+	case Int(8):
+		return castInts(col, to, strict, math.MinInt8, math.MaxInt8, NewInt8ColumnOwned)
+	case UInt(64):
+		return castUints(col, to, strict, math.MaxUint64, NewUInt64ColumnOwned)
+	case UInt(32):
+		return castUints(col, to, strict, math.MaxUint32, NewUInt32ColumnOwned)
+	case UInt(16):
+		return castUints(col, to, strict, math.MaxUint16, NewUInt16ColumnOwned)
+	case UInt(8):
+		return castUints(col, to, strict, math.MaxUint8, NewUInt8ColumnOwned)
+	case Float(64):
+		return castFloats(col, to, strict, NewFloat64ColumnOwned)
+	case Float(32):
+		return castFloats(col, to, strict, NewFloat32ColumnOwned)
+	default:
+		return nil, nil
+	}
+}
+
+func castInts[T ~int8 | ~int16 | ~int32 | ~int64](col Column, to DataType, strict bool, lo, hi int64, build func(string, []T, Bitmap) Column) (Column, error) {
+	get, ok := castSourceInt64(col)
+	if !ok {
+		return nil, nil
+	}
+	return castRows(col, to, strict, build, func(i int) (T, bool) {
+		v, ok := get(i)
+		if !ok || v < lo || v > hi {
+			return 0, false
+		}
+		return T(v), true
+	})
+}
+
+func castUints[T ~uint8 | ~uint16 | ~uint32 | ~uint64](col Column, to DataType, strict bool, hi uint64, build func(string, []T, Bitmap) Column) (Column, error) {
+	get, ok := castSourceUint64(col)
+	if !ok {
+		return nil, nil
+	}
+	return castRows(col, to, strict, build, func(i int) (T, bool) {
+		v, ok := get(i)
+		if !ok || v > hi {
+			return 0, false
+		}
+		return T(v), true
+	})
+}
+
+func castFloats[T ~float32 | ~float64](col Column, to DataType, strict bool, build func(string, []T, Bitmap) Column) (Column, error) {
+	get, ok := castSourceFloat64(col)
+	if !ok {
+		return nil, nil
+	}
+	return castRows(col, to, strict, build, func(i int) (T, bool) {
+		v, ok := get(i)
+		// Finite values beyond float32's range would round to an infinity.
+		if ok && math.IsInf(float64(T(v)), 0) && !math.IsInf(v, 0) {
+			return 0, false
+		}
+		return T(v), ok
+	})
+}
+
+// castRows builds a column from get, which reports false for values that do
+// not convert: those become NULL, or fail the cast when strict. NULL rows of
+// col are not passed to get.
+func castRows[T any](col Column, to DataType, strict bool, build func(string, []T, Bitmap) Column, get func(int) (T, bool)) (Column, error) {
+	n := col.Len()
+	data := make([]T, n)
+	valid := make([]bool, n)
+	for i := 0; i < n; i++ {
+		if col.IsNull(i) {
+			continue
+		}
+		data[i], valid[i] = get(i)
+		if strict && !valid[i] {
+			return nil, fmt.Errorf("cast %s of %s to %s: row %d value %q does not convert", col.Name(), col.DType(), to, i, col.ValueString(i))
+		}
+	}
+	return build(col.Name(), data, NewBitmapFromBools(valid)), nil
+}
+
+func castSourceInt64(col Column) (func(int) (int64, bool), bool) {
+	if get, ok := Int64Accessor(col); ok {
+		return func(i int) (int64, bool) { return get(i), true }, true
+	}
+	switch c := col.(type) {
+	case *UInt64Column:
+		return func(i int) (int64, bool) {
+			v := c.Value(i)
+			return int64(v), v <= math.MaxInt64
+		}, true
+	case *Float64Column, *Float32Column:
+		get, _ := Float64Accessor(c)
+		return func(i int) (int64, bool) {
+			v := get(i)
+			if math.IsNaN(v) || v < -9.223372036854775808e18 || v >= 9.223372036854775808e18 {
+				return 0, false
+			}
+			return int64(v), true
+		}, true
+	case *BoolColumn:
+		return func(i int) (int64, bool) {
+			if c.Value(i) {
+				return 1, true
+			}
+			return 0, true
+		}, true
+	case *Utf8Column:
+		return func(i int) (int64, bool) {
+			v, err := strconv.ParseInt(c.Value(i), 10, 64)
+			return v, err == nil
+		}, true
+	default:
+		return nil, false
+	}
+}
+
+func castSourceUint64(col Column) (func(int) (uint64, bool), bool) {
+	if get, ok := Int64Accessor(col); ok {
+		return func(i int) (uint64, bool) {
+			v := get(i)
+			return uint64(v), v >= 0
+		}, true
+	}
+	switch c := col.(type) {
+	case *UInt64Column:
+		return func(i int) (uint64, bool) { return c.Value(i), true }, true
+	case *Float64Column, *Float32Column:
+		get, _ := Float64Accessor(c)
+		return func(i int) (uint64, bool) {
+			v := get(i)
+			if math.IsNaN(v) || v < 0 || v >= 1.8446744073709552e19 {
+				return 0, false
+			}
+			return uint64(v), true
+		}, true
+	case *BoolColumn:
+		return func(i int) (uint64, bool) {
+			if c.Value(i) {
+				return 1, true
+			}
+			return 0, true
+		}, true
+	case *Utf8Column:
+		return func(i int) (uint64, bool) {
+			v, err := strconv.ParseUint(c.Value(i), 10, 64)
+			return v, err == nil
+		}, true
+	default:
+		return nil, false
+	}
+}
+
+func castSourceFloat64(col Column) (func(int) (float64, bool), bool) {
+	if get, ok := Float64Accessor(col); ok {
+		return func(i int) (float64, bool) { return get(i), true }, true
+	}
+	switch c := col.(type) {
+	case *BoolColumn:
+		return func(i int) (float64, bool) {
+			if c.Value(i) {
+				return 1, true
+			}
+			return 0, true
+		}, true
+	case *Utf8Column:
+		return func(i int) (float64, bool) {
+			v, err := strconv.ParseFloat(c.Value(i), 64)
+			return v, err == nil
+		}, true
+	default:
+		return nil, false
+	}
+}
+
+func castSourceBool(col Column) (func(int) (bool, bool), bool) {
+	if get, ok := Float64Accessor(col); ok {
+		return func(i int) (bool, bool) { return get(i) != 0, true }, true
+	}
+	switch c := col.(type) {
+	case *Utf8Column:
+		return func(i int) (bool, bool) {
+			v, err := strconv.ParseBool(c.Value(i))
+			return v, err == nil
+		}, true
+	default:
+		return nil, false
+	}
+}
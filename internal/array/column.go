@@ -15,6 +15,7 @@ type Column interface {
 	IsNull(i int) bool
 	ValueString(i int) string
 	Filter(mask []bool) Column
+	// Take gathers rows by index. A negative index yields a NULL row.
 	Take(order []int) Column
 }
 
@@ -68,6 +69,10 @@ func (c *typedColumn[T]) Take(order []int) Column {
 	valid := BitmapBuilder{}
 	for i := range order {
 		row := order[i]
+		if row < 0 {
+			valid.Append(false)
+			continue
+		}
 		out[i] = c.data[row]
 		valid.Append(!c.IsNull(row))
 	}
@@ -177,6 +182,11 @@ func (c *Utf8Column) Take(order []int) Column {
 	valid := BitmapBuilder{}
 	for i := range order {
 		row := order[i]
+		if row < 0 {
+			offsets = append(offsets, int32(len(bytesOut)))
+			valid.Append(false)
+			continue
+		}
 		s, e := c.byteRange(row)
 		bytesOut = append(bytesOut, c.bytes[s:e]...)
 		offsets = append(offsets, int32(len(bytesOut)))
@@ -0,0 +1,87 @@
+package array
+
+import "fmt"
+
+// Concat appends the rows of cols into one new column named after the first.
+// All columns must share the same data type.
+func Concat(cols ...Column) (Column, error) {
+	if len(cols) == 0 {
+		return nil, fmt.Errorf("concat requires at least one column")
+	}
+	dt := cols[0].DType()
+	for _, c := range cols[1:] {
+		if c.DType() != dt {
+			return nil, fmt.Errorf("concat dtype mismatch: %s vs %s", dt, c.DType())
+		}
+	}
+	switch first := cols[0].(type) {
+	case *Int64Column:
+		return concatTyped(&first.typedColumn, cols, func(c Column) *typedColumn[int64] { return &c.(*Int64Column).typedColumn }), nil
+	case *Float64Column:
+		return concatTyped(&first.typedColumn, cols, func(c Column) *typedColumn[float64] { return &c.(*Float64Column).typedColumn }), nil
+	case *Int32Column:
+		return concatTyped(&first.typedColumn, cols, func(c Column) *typedColumn[int32] { return &c.(*Int32Column).typedColumn }), nil
+	case *Int16Column:
+		return concatTyped(&first.typedColumn, cols, func(c Column) *typedColumn[int16] { return &c.(*Int16Column).typedColumn }), nil
+	case *Int8Column:
+		return concatTyped(&first.typedColumn, cols, func(c Column) *typedColumn[int8] { return &c.(*Int8Column).typedColumn }), nil
+	case *UInt64Column:
+		return concatTyped(&first.typedColumn, cols, func(c Column) *typedColumn[uint64] { return &c.(*UInt64Column).typedColumn }), nil
+	case *UInt32Column:
+		return concatTyped(&first.typedColumn, cols, func(c Column) *typedColumn[uint32] { return &c.(*UInt32Column).typedColumn }), nil
+	case *UInt16Column:
+		return concatTyped(&first.typedColumn, cols, func(c Column) *typedColumn[uint16] { return &c.(*UInt16Column).typedColumn }), nil
+	case *UInt8Column:
+		return concatTyped(&first.typedColumn, cols, func(c Column) *typedColumn[uint8] { return &c.(*UInt8Column).typedColumn }), nil
+	case *Float32Column:
+		return concatTyped(&first.typedColumn, cols, func(c Column) *typedColumn[float32] { return &c.(*Float32Column).typedColumn }), nil
+	case *BoolColumn:
+		return concatTyped(&first.typedColumn, cols, func(c Column) *typedColumn[bool] { return &c.(*BoolColumn).typedColumn }), nil
+	case *DateColumn:
+		return concatTyped(&first.typedColumn, cols, func(c Column) *typedColumn[int32] { return &c.(*DateColumn).typedColumn }), nil
+	case *DatetimeColumn:
+		return concatTyped(&first.typedColumn, cols, func(c Column) *typedColumn[int64] { return &c.(*DatetimeColumn).typedColumn }), nil
+	case *DurationColumn:
+		return concatTyped(&first.typedColumn, cols, func(c Column) *typedColumn[int64] { return &c.(*DurationColumn).typedColumn }), nil
+	case *Utf8Column:
+		n, size := 0, 0
+		for _, c := range cols {
+			n += c.Len()
+			size += len(c.(*Utf8Column).bytes)
+		}
+		offsets := make([]int32, 1, n+1)
+		buf := make([]byte, 0, size)
+		valid := BitmapBuilder{}
+		valid.Reserve(n)
+		for _, c := range cols {
+			u := c.(*Utf8Column)
+			for i := 0; i < u.Len(); i++ {
+				s, e := u.byteRange(i)
+				buf = append(buf, u.bytes[s:e]...)
+				offsets = append(offsets, int32(len(buf)))
+				valid.Append(!u.IsNull(i))
+			}
+		}
+		return NewUtf8ColumnOwned(first.name, offsets, buf, valid.Build()), nil
+	default:
+		return nil, fmt.Errorf("unsupported column type for concat")
+	}
+}
+
+func concatTyped[T any](first *typedColumn[T], cols []Column, typed func(Column) *typedColumn[T]) Column {
+	n := 0
+	for _, c := range cols {
+		n += c.Len()
+	}
+	data := make([]T, 0, n)
+	valid := BitmapBuilder{}
+	valid.Reserve(n)
+	for _, c := range cols {
+		t := typed(c)
+		data = append(data, t.data...)
+		for i := range t.data {
+			valid.Append(t.valid.Get(i))
+		}
+	}
+	return first.build(first.name, data, valid.Build())
+}
@@ -0,0 +1,200 @@
+package array
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Narrow numeric columns store values at their native width. Kernels that are
+// specialized on int64 and float64 read them through Int64Accessor and
+// Float64Accessor, which widen one row at a time without copying the column.
+type (
+	Int32Column   struct{ numericColumn[int32] }
+	Int16Column   struct{ numericColumn[int16] }
+	Int8Column    struct{ numericColumn[int8] }
+	UInt64Column  struct{ numericColumn[uint64] }
+	UInt32Column  struct{ numericColumn[uint32] }
+	UInt16Column  struct{ numericColumn[uint16] }
+	UInt8Column   struct{ numericColumn[uint8] }
+	Float32Column struct{ numericColumn[float32] }
+)
+
+type numericColumn[T int8 | int16 | int32 | uint8 | uint16 | uint32 | uint64 | float32] struct {
+	typedColumn[T]
+}
+
+func (c *numericColumn[T]) Value(i int) T    { return c.data[i] }
+func (c *numericColumn[T]) Validity() Bitmap { return c.valid }
+func (c *numericColumn[T]) Values() []T {
+	out := make([]T, len(c.data))
+	copy(out, c.data)
+	return out
+}
+
+func newNumericColumn[T int8 | int16 | int32 | uint8 | uint16 | uint32 | uint64 | float32](name string, data []T, valid []bool, owned func(string, []T, Bitmap) Column) (Column, error) {
+	var v Bitmap
+	if valid == nil {
+		v = NewBitmap(len(data), true)
+	} else {
+		if len(valid) != len(data) {
+			return nil, fmt.Errorf("valid length %d != data length %d", len(valid), len(data))
+		}
+		v = NewBitmapFromBools(valid)
+	}
+	return owned(name, append([]T(nil), data...), v), nil
+}
+
+func NewInt32Column(name string, data []int32, valid []bool) (*Int32Column, error) {
+	c, err := newNumericColumn(name, data, valid, NewInt32ColumnOwned)
+	if err != nil {
+		return nil, err
+	}
+	return c.(*Int32Column), nil
+}
+
+func NewInt16Column(name string, data []int16, valid []bool) (*Int16Column, error) {
+	c, err := newNumericColumn(name, data, valid, NewInt16ColumnOwned)
+	if err != nil {
+		return nil, err
+	}
+	return c.(*Int16Column), nil
+}
+
+func NewInt8Column(name string, data []int8, valid []bool) (*Int8Column, error) {
+	c, err := newNumericColumn(name, data, valid, NewInt8ColumnOwned)
+	if err != nil {
+		return nil, err
+	}
+	return c.(*Int8Column), nil
+}
+
+func NewUInt64Column(name string, data []uint64, valid []bool) (*UInt64Column, error) {
+	c, err := newNumericColumn(name, data, valid, NewUInt64ColumnOwned)
+	if err != nil {
+		return nil, err
+	}
+	return c.(*UInt64Column), nil
+}
+
+func NewUInt32Column(name string, data []uint32, valid []bool) (*UInt32Column, error) {
+	c, err := newNumericColumn(name, data, valid, NewUInt32ColumnOwned)
+	if err != nil {
+		return nil, err
+	}
+	return c.(*UInt32Column), nil
+}
+
+func NewUInt16Column(name string, data []uint16, valid []bool) (*UInt16Column, error) {
+	c, err := newNumericColumn(name, data, valid, NewUInt16ColumnOwned)
+	if err != nil {
+		return nil, err
+	}
+	return c.(*UInt16Column), nil
+}
+
+func NewUInt8Column(name string, data []uint8, valid []bool) (*UInt8Column, error) {
+	c, err := newNumericColumn(name, data, valid, NewUInt8ColumnOwned)
+	if err != nil {
+		return nil, err
+	}
+	return c.(*UInt8Column), nil
+}
+
+func NewFloat32Column(name string, data []float32, valid []bool) (*Float32Column, error) {
+	c, err := newNumericColumn(name, data, valid, NewFloat32ColumnOwned)
+	if err != nil {
+		return nil, err
+	}
+	return c.(*Float32Column), nil
+}
+
+func NewInt32ColumnOwned(name string, data []int32, valid Bitmap) Column {
+	return &Int32Column{numericColumn[int32]{typedColumn[int32]{name: name, data: data, valid: valid, ops: int32Ops, build: NewInt32ColumnOwned}}}
+}
+
+func NewInt16ColumnOwned(name string, data []int16, valid Bitmap) Column {
+	return &Int16Column{numericColumn[int16]{typedColumn[int16]{name: name, data: data, valid: valid, ops: int16Ops, build: NewInt16ColumnOwned}}}
+}
+
+func NewInt8ColumnOwned(name string, data []int8, valid Bitmap) Column {
+	return &Int8Column{numericColumn[int8]{typedColumn[int8]{name: name, data: data, valid: valid, ops: int8Ops, build: NewInt8ColumnOwned}}}
+}
+
+func NewUInt64ColumnOwned(name string, data []uint64, valid Bitmap) Column {
+	return &UInt64Column{numericColumn[uint64]{typedColumn[uint64]{name: name, data: data, valid: valid, ops: uint64Ops, build: NewUInt64ColumnOwned}}}
+}
+
+func NewUInt32ColumnOwned(name string, data []uint32, valid Bitmap) Column {
+	return &UInt32Column{numericColumn[uint32]{typedColumn[uint32]{name: name, data: data, valid: valid, ops: uint32Ops, build: NewUInt32ColumnOwned}}}
+}
+
+func NewUInt16ColumnOwned(name string, data []uint16, valid Bitmap) Column {
+	return &UInt16Column{numericColumn[uint16]{typedColumn[uint16]{name: name, data: data, valid: valid, ops: uint16Ops, build: NewUInt16ColumnOwned}}}
+}
+
+func NewUInt8ColumnOwned(name string, data []uint8, valid Bitmap) Column {
+	return &UInt8Column{numericColumn[uint8]{typedColumn[uint8]{name: name, data: data, valid: valid, ops: uint8Ops, build: NewUInt8ColumnOwned}}}
+}
+
+func NewFloat32ColumnOwned(name string, data []float32, valid Bitmap) Column {
+	return &Float32Column{numericColumn[float32]{typedColumn[float32]{name: name, data: data, valid: valid, ops: float32Ops, build: NewFloat32ColumnOwned}}}
+}
+
+var (
+	int32Ops   = typeOps[int32]{dtype: Int(32), toString: func(v int32) string { return strconv.FormatInt(int64(v), 10) }}
+	int16Ops   = typeOps[int16]{dtype: Int(16), toString: func(v int16) string { return strconv.FormatInt(int64(v), 10) }}
+	int8Ops    = typeOps[int8]{dtype: Int(8), toString: func(v int8) string { return strconv.FormatInt(int64(v), 10) }}
+	uint64Ops  = typeOps[uint64]{dtype: UInt(64), toString: func(v uint64) string { return strconv.FormatUint(v, 10) }}
+	uint32Ops  = typeOps[uint32]{dtype: UInt(32), toString: func(v uint32) string { return strconv.FormatUint(uint64(v), 10) }}
+	uint16Ops  = typeOps[uint16]{dtype: UInt(16), toString: func(v uint16) string { return strconv.FormatUint(uint64(v), 10) }}
+	uint8Ops   = typeOps[uint8]{dtype: UInt(8), toString: func(v uint8) string { return strconv.FormatUint(uint64(v), 10) }}
+	float32Ops = typeOps[float32]{dtype: Float(32), toString: func(v float32) string { return strconv.FormatFloat(float64(v), 'g', -1, 32) }}
+)
+
+// IsNumeric reports whether t is a signed, unsigned or float type.
+func IsNumeric(t DataType) bool {
+	return t.Kind == KindInt || t.Kind == KindUInt || t.Kind == KindFloat
+}
+
+// IsInteger reports whether t is a signed or unsigned integer type.
+func IsInteger(t DataType) bool { return t.Kind == KindInt || t.Kind == KindUInt }
+
+// Int64Accessor returns an int64 view of the integer columns whose values all
+// fit in int64: every signed width and unsigned widths below 64 bits.
+func Int64Accessor(col Column) (func(int) int64, bool) {
+	switch c := col.(type) {
+	case *Int64Column:
+		return c.Value, true
+	case *Int32Column:
+		return func(i int) int64 { return int64(c.data[i]) }, true
+	case *Int16Column:
+		return func(i int) int64 { return int64(c.data[i]) }, true
+	case *Int8Column:
+		return func(i int) int64 { return int64(c.data[i]) }, true
+	case *UInt32Column:
+		return func(i int) int64 { return int64(c.data[i]) }, true
+	case *UInt16Column:
+		return func(i int) int64 { return int64(c.data[i]) }, true
+	case *UInt8Column:
+		return func(i int) int64 { return int64(c.data[i]) }, true
+	default:
+		return nil, false
+	}
+}
+
+// Float64Accessor returns a float64 view of any numeric column. uint64 and
+// int64 values beyond 2^53 lose precision.
+func Float64Accessor(col Column) (func(int) float64, bool) {
+	switch c := col.(type) {
+	case *Float64Column:
+		return c.Value, true
+	case *Float32Column:
+		return func(i int) float64 { return float64(c.data[i]) }, true
+	case *UInt64Column:
+		return func(i int) float64 { return float64(c.data[i]) }, true
+	}
+	if get, ok := Int64Accessor(col); ok {
+		return func(i int) float64 { return float64(get(i)) }, true
+	}
+	return nil, false
+}
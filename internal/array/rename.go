@@ -22,6 +22,38 @@ func WithName(col Column, name string) (Column, error) {
 		out := *c
 		out.name = name
 		return &out, nil
+	case *Int32Column:
+		out := *c
+		out.name = name
+		return &out, nil
+	case *Int16Column:
+		out := *c
+		out.name = name
+		return &out, nil
+	case *Int8Column:
+		out := *c
+		out.name = name
+		return &out, nil
+	case *UInt64Column:
+		out := *c
+		out.name = name
+		return &out, nil
+	case *UInt32Column:
+		out := *c
+		out.name = name
+		return &out, nil
+	case *UInt16Column:
+		out := *c
+		out.name = name
+		return &out, nil
+	case *UInt8Column:
+		out := *c
+		out.name = name
+		return &out, nil
+	case *Float32Column:
+		out := *c
+		out.name = name
+		return &out, nil
 	case *BoolColumn:
 		out := *c
 		out.name = name
@@ -30,6 +62,18 @@ func WithName(col Column, name string) (Column, error) {
 		out := *c
 		out.name = name
 		return &out, nil
+	case *DateColumn:
+		out := *c
+		out.name = name
+		return &out, nil
+	case *DatetimeColumn:
+		out := *c
+		out.name = name
+		return &out, nil
+	case *DurationColumn:
+		out := *c
+		out.name = name
+		return &out, nil
 	default:
 		return nil, fmt.Errorf("unsupported column type for rename")
 	}
@@ -0,0 +1,284 @@
+package array
+
+import (
+	"fmt"
+	"time"
+)
+
+// Temporal columns store integer offsets from the Unix epoch:
+// Date as days (int32), Datetime and Duration as counts of their TimeUnit (int64).
+// Datetime values are always instants; TZ only controls how they are rendered.
+type (
+	DateColumn     struct{ typedColumn[int32] }
+	DatetimeColumn struct {
+		typedColumn[int64]
+		loc *time.Location
+	}
+	DurationColumn struct{ typedColumn[int64] }
+)
+
+const secondsPerDay = 24 * 60 * 60
+
+const (
+	dateLayout          = "2006-01-02"
+	naiveDatetimeLayout = "2006-01-02T15:04:05.999999999"
+)
+
+func (c *DateColumn) Value(i int) int32 { return c.data[i] }
+func (c *DateColumn) Values() []int32 {
+	out := make([]int32, len(c.data))
+	copy(out, c.data)
+	return out
+}
+func (c *DateColumn) Validity() Bitmap      { return c.valid }
+func (c *DateColumn) Time(i int) time.Time  { return DateToTime(c.data[i]) }
+func (c *DatetimeColumn) Value(i int) int64 { return c.data[i] }
+func (c *DatetimeColumn) Validity() Bitmap  { return c.valid }
+func (c *DatetimeColumn) Unit() TimeUnit    { return c.ops.dtype.Unit }
+func (c *DatetimeColumn) TZ() string        { return c.ops.dtype.TZ }
+
+// Location returns the column's time zone, or nil for naive datetimes.
+func (c *DatetimeColumn) Location() *time.Location { return c.loc }
+func (c *DatetimeColumn) Values() []int64 {
+	out := make([]int64, len(c.data))
+	copy(out, c.data)
+	return out
+}
+func (c *DatetimeColumn) Time(i int) time.Time {
+	t := UnitToTime(c.data[i], c.ops.dtype.Unit)
+	if c.loc == nil {
+		return t
+	}
+	return t.In(c.loc)
+}
+func (c *DurationColumn) Value(i int) int64 { return c.data[i] }
+func (c *DurationColumn) Validity() Bitmap  { return c.valid }
+func (c *DurationColumn) Unit() TimeUnit    { return c.ops.dtype.Unit }
+func (c *DurationColumn) Values() []int64 {
+	out := make([]int64, len(c.data))
+	copy(out, c.data)
+	return out
+}
+func (c *DurationColumn) Duration(i int) time.Duration {
+	return UnitToDuration(c.data[i], c.ops.dtype.Unit)
+}
+
+// DateFromTime returns the calendar date of t (in t's location) as days since the epoch.
+func DateFromTime(t time.Time) int32 {
+	y, m, d := t.Date()
+	return int32(time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix() / secondsPerDay)
+}
+
+func DateToTime(days int32) time.Time {
+	return time.Unix(int64(days)*secondsPerDay, 0).UTC()
+}
+
+func TimeToUnit(t time.Time, unit TimeUnit) int64 {
+	switch unit {
+	case TimeUnitMS:
+		return t.UnixMilli()
+	case TimeUnitUS:
+		return t.UnixMicro()
+	default:
+		return t.UnixNano()
+	}
+}
+
+func UnitToTime(v int64, unit TimeUnit) time.Time {
+	switch unit {
+	case TimeUnitMS:
+		return time.UnixMilli(v).UTC()
+	case TimeUnitUS:
+		return time.UnixMicro(v).UTC()
+	default:
+		return time.Unix(0, v).UTC()
+	}
+}
+
+// UnitNanos returns the number of nanoseconds in one tick of unit.
+func UnitNanos(unit TimeUnit) int64 {
+	switch unit {
+	case TimeUnitMS:
+		return int64(time.Millisecond)
+	case TimeUnitUS:
+		return int64(time.Microsecond)
+	default:
+		return 1
+	}
+}
+
+func DurationToUnit(d time.Duration, unit TimeUnit) int64 {
+	return int64(d) / UnitNanos(unit)
+}
+
+func UnitToDuration(v int64, unit TimeUnit) time.Duration {
+	return time.Duration(v * UnitNanos(unit))
+}
+
+// FormatDate renders days since the epoch as an ISO-8601 date.
+func FormatDate(days int32) string { return DateToTime(days).Format(dateLayout) }
+
+// FormatDatetime renders an instant as ISO-8601. Naive values (loc == nil)
+// are printed without an offset.
+func FormatDatetime(v int64, unit TimeUnit, loc *time.Location) string {
+	t := UnitToTime(v, unit)
+	if loc == nil {
+		return t.Format(naiveDatetimeLayout)
+	}
+	return t.In(loc).Format(time.RFC3339Nano)
+}
+
+// ParseDate parses an ISO-8601 date (YYYY-MM-DD).
+func ParseDate(s string) (int32, error) {
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		return 0, err
+	}
+	return DateFromTime(t), nil
+}
+
+// ParseDatetime parses an ISO-8601 datetime. Values without an offset are
+// interpreted in loc (UTC when loc is nil).
+func ParseDatetime(s string, unit TimeUnit, loc *time.Location) (int64, error) {
+	v, _, err := ParseDatetimeOffset(s, unit, loc)
+	return v, err
+}
+
+// ParseDatetimeOffset is ParseDatetime that also reports whether s carried an
+// explicit UTC offset (or Z suffix). Both 'T' and ' ' date/time separators are
+// accepted, with optional fractional seconds; a bare date parses as midnight.
+func ParseDatetimeOffset(s string, unit TimeUnit, loc *time.Location) (int64, bool, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	if len(s) < len(dateLayout) || s[4] != '-' {
+		return 0, false, fmt.Errorf("cannot parse %q as datetime", s)
+	}
+	for _, layout := range offsetDatetimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return TimeToUnit(t, unit), true, nil
+		}
+	}
+	for _, layout := range naiveDatetimeLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return TimeToUnit(t, unit), false, nil
+		}
+	}
+	return 0, false, fmt.Errorf("cannot parse %q as datetime", s)
+}
+
+var (
+	offsetDatetimeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999Z07:00"}
+	naiveDatetimeLayouts  = []string{naiveDatetimeLayout, "2006-01-02 15:04:05.999999999", "2006-01-02T15:04", "2006-01-02 15:04", dateLayout}
+)
+
+func validTimeUnit(unit TimeUnit) error {
+	switch unit {
+	case TimeUnitNS, TimeUnitUS, TimeUnitMS:
+		return nil
+	default:
+		return fmt.Errorf("invalid time unit")
+	}
+}
+
+// LoadTZ resolves a DataType TZ string. An empty TZ yields a nil location (naive).
+func LoadTZ(tz string) (*time.Location, error) {
+	if tz == "" {
+		return nil, nil
+	}
+	return time.LoadLocation(tz)
+}
+
+func NewDateColumn(name string, data []int32, valid []bool) (*DateColumn, error) {
+	v, err := validityFromBools(len(data), valid)
+	if err != nil {
+		return nil, err
+	}
+	return NewDateColumnOwned(name, append([]int32(nil), data...), v).(*DateColumn), nil
+}
+
+func NewDatetimeColumn(name string, unit TimeUnit, tz string, data []int64, valid []bool) (*DatetimeColumn, error) {
+	if err := validTimeUnit(unit); err != nil {
+		return nil, err
+	}
+	loc, err := LoadTZ(tz)
+	if err != nil {
+		return nil, err
+	}
+	v, err := validityFromBools(len(data), valid)
+	if err != nil {
+		return nil, err
+	}
+	return newDatetimeColumn(name, append([]int64(nil), data...), v, datetimeOps(unit, tz, loc), loc), nil
+}
+
+func NewDurationColumn(name string, unit TimeUnit, data []int64, valid []bool) (*DurationColumn, error) {
+	if err := validTimeUnit(unit); err != nil {
+		return nil, err
+	}
+	v, err := validityFromBools(len(data), valid)
+	if err != nil {
+		return nil, err
+	}
+	return NewDurationColumnOwned(name, append([]int64(nil), data...), v, unit).(*DurationColumn), nil
+}
+
+func NewDateColumnOwned(name string, data []int32, valid Bitmap) Column {
+	return &DateColumn{typedColumn[int32]{name: name, data: data, valid: valid, ops: dateOps, build: NewDateColumnOwned}}
+}
+
+// NewDatetimeColumnOwned panics if tz cannot be resolved; callers are expected
+// to pass a TZ taken from an existing, validated DataType.
+func NewDatetimeColumnOwned(name string, data []int64, valid Bitmap, unit TimeUnit, tz string) Column {
+	loc, err := LoadTZ(tz)
+	if err != nil {
+		panic(err)
+	}
+	return newDatetimeColumn(name, data, valid, datetimeOps(unit, tz, loc), loc)
+}
+
+func NewDurationColumnOwned(name string, data []int64, valid Bitmap, unit TimeUnit) Column {
+	ops := durationOps(unit)
+	var build func(name string, data []int64, valid Bitmap) Column
+	build = func(name string, data []int64, valid Bitmap) Column {
+		return &DurationColumn{typedColumn[int64]{name: name, data: data, valid: valid, ops: ops, build: build}}
+	}
+	return build(name, data, valid)
+}
+
+func newDatetimeColumn(name string, data []int64, valid Bitmap, ops typeOps[int64], loc *time.Location) *DatetimeColumn {
+	c := &DatetimeColumn{typedColumn: typedColumn[int64]{name: name, data: data, valid: valid, ops: ops}, loc: loc}
+	c.build = func(name string, data []int64, valid Bitmap) Column {
+		return newDatetimeColumn(name, data, valid, ops, loc)
+	}
+	return c
+}
+
+func validityFromBools(n int, valid []bool) (Bitmap, error) {
+	if valid == nil {
+		return NewBitmap(n, true), nil
+	}
+	if len(valid) != n {
+		return Bitmap{}, fmt.Errorf("valid length %d != data length %d", len(valid), n)
+	}
+	return NewBitmapFromBools(valid), nil
+}
+
+var dateOps = typeOps[int32]{
+	dtype:    Date(),
+	toString: FormatDate,
+}
+
+func datetimeOps(unit TimeUnit, tz string, loc *time.Location) typeOps[int64] {
+	return typeOps[int64]{
+		dtype:    Datetime(unit, tz),
+		toString: func(v int64) string { return FormatDatetime(v, unit, loc) },
+	}
+}
+
+func durationOps(unit TimeUnit) typeOps[int64] {
+	return typeOps[int64]{
+		dtype:    Duration(unit),
+		toString: func(v int64) string { return UnitToDuration(v, unit).String() },
+	}
+}
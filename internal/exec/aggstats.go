@@ -0,0 +1,286 @@
+package exec
+
+import (
+	"encoding/binary"
+	"math"
+	"sort"
+
+	"grizzly/internal/array"
+)
+
+// aggQuantile buffers each group's non-null values and selects the quantile
+// after sorting. Median is the 0.5 quantile with linear interpolation.
+type aggQuantile struct {
+	alias  string
+	col    array.Column
+	value  func(int) float64
+	q      float64
+	interp QuantileInterpolation
+	groups [][]float64
+}
+
+func (a *aggQuantile) grow(n int) { a.groups = growTo(a.groups, n, nil) }
+func (a *aggQuantile) update(gids []int, base int) {
+	for i, g := range gids {
+		row := base + i
+		if a.col.IsNull(row) {
+			continue
+		}
+		a.groups[g] = append(a.groups[g], a.value(row))
+	}
+}
+func (a *aggQuantile) merge(src aggResolved, remap []int) {
+	for g, vals := range src.(*aggQuantile).groups {
+		a.groups[remap[g]] = append(a.groups[remap[g]], vals...)
+	}
+}
+func (a *aggQuantile) fresh() aggResolved {
+	return &aggQuantile{alias: a.alias, col: a.col, value: a.value, q: a.q, interp: a.interp}
+}
+func (a *aggQuantile) build() array.Column {
+	out := make([]float64, len(a.groups))
+	valid := make([]bool, len(a.groups))
+	for i, vals := range a.groups {
+		if len(vals) == 0 {
+			continue
+		}
+		sort.Float64s(vals)
+		out[i] = quantileSorted(vals, a.q, a.interp)
+		valid[i] = true
+	}
+	return array.NewFloat64ColumnOwned(a.alias, out, array.NewBitmapFromBools(valid))
+}
+
+func quantileSorted(vals []float64, q float64, interp QuantileInterpolation) float64 {
+	pos := q * float64(len(vals)-1)
+	lo := int(math.Floor(pos))
+	hi := int(math.Ceil(pos))
+	switch interp {
+	case QuantileLower:
+		return vals[lo]
+	case QuantileHigher:
+		return vals[hi]
+	case QuantileMidpoint:
+		return (vals[lo] + vals[hi]) / 2
+	case QuantileLinear:
+		return vals[lo] + (vals[hi]-vals[lo])*(pos-float64(lo))
+	default:
+		return vals[int(math.Round(pos))]
+	}
+}
+
+// aggVariance uses Welford's online update, which stays numerically stable
+// for large groups; partitions are combined with Chan's parallel formula.
+type aggVariance struct {
+	alias string
+	col   array.Column
+	value func(int) float64
+	ddof  int
+	std   bool
+	count []int64
+	mean  []float64
+	m2    []float64
+}
+
+func (a *aggVariance) grow(n int) {
+	a.count = growTo(a.count, n, 0)
+	a.mean = growTo(a.mean, n, 0)
+	a.m2 = growTo(a.m2, n, 0)
+}
+func (a *aggVariance) update(gids []int, base int) {
+	for i, g := range gids {
+		row := base + i
+		if a.col.IsNull(row) {
+			continue
+		}
+		v := a.value(row)
+		a.count[g]++
+		delta := v - a.mean[g]
+		a.mean[g] += delta / float64(a.count[g])
+		a.m2[g] += delta * (v - a.mean[g])
+	}
+}
+func (a *aggVariance) merge(src aggResolved, remap []int) {
+	s := src.(*aggVariance)
+	for g, d := range remap {
+		nb := s.count[g]
+		if nb == 0 {
+			continue
+		}
+		na := a.count[d]
+		n := na + nb
+		delta := s.mean[g] - a.mean[d]
+		a.mean[d] += delta * float64(nb) / float64(n)
+		a.m2[d] += s.m2[g] + delta*delta*float64(na)*float64(nb)/float64(n)
+		a.count[d] = n
+	}
+}
+func (a *aggVariance) fresh() aggResolved {
+	return &aggVariance{alias: a.alias, col: a.col, value: a.value, ddof: a.ddof, std: a.std}
+}
+func (a *aggVariance) build() array.Column {
+	out := make([]float64, len(a.count))
+	valid := make([]bool, len(a.count))
+	for i := range out {
+		denom := a.count[i] - int64(a.ddof)
+		if a.count[i] == 0 || denom <= 0 {
+			continue
+		}
+		out[i] = a.m2[i] / float64(denom)
+		if a.std {
+			out[i] = math.Sqrt(out[i])
+		}
+		valid[i] = true
+	}
+	return array.NewFloat64ColumnOwned(a.alias, out, array.NewBitmapFromBools(valid))
+}
+
+// aggNUnique tracks (group, value) pairs in one set keyed by the group id
+// followed by the encoded value, so NULL counts as one distinct value.
+type aggNUnique struct {
+	alias  string
+	enc    *keyEncoder
+	seen   map[string]struct{}
+	counts []int64
+	buf    []byte
+}
+
+func (a *aggNUnique) grow(n int) { a.counts = growTo(a.counts, n, 0) }
+func (a *aggNUnique) update(gids []int, base int) {
+	for i, g := range gids {
+		a.buf = binary.LittleEndian.AppendUint64(a.buf[:0], uint64(g))
+		a.buf = a.enc.appendRow(a.buf, base+i)
+		a.insert(g)
+	}
+}
+func (a *aggNUnique) insert(g int) {
+	if _, ok := a.seen[string(a.buf)]; ok {
+		return
+	}
+	a.seen[string(a.buf)] = struct{}{}
+	a.counts[g]++
+}
+func (a *aggNUnique) merge(src aggResolved, remap []int) {
+	for k := range src.(*aggNUnique).seen {
+		d := remap[binary.LittleEndian.Uint64([]byte(k[:8]))]
+		a.buf = binary.LittleEndian.AppendUint64(a.buf[:0], uint64(d))
+		a.buf = append(a.buf, k[8:]...)
+		a.insert(d)
+	}
+}
+func (a *aggNUnique) fresh() aggResolved {
+	return &aggNUnique{alias: a.alias, enc: a.enc, seen: make(map[string]struct{}, 256)}
+}
+func (a *aggNUnique) build() array.Column {
+	return array.NewInt64ColumnOwned(a.alias, a.counts, array.NewBitmap(len(a.counts), true))
+}
+
+// aggPick implements First and Last by remembering a row per group and
+// gathering the values at build time, so it works for any dtype.
+type aggPick struct {
+	alias string
+	col   array.Column
+	last  bool
+	rows  []int
+}
+
+func (a *aggPick) grow(n int) { a.rows = growTo(a.rows, n, -1) }
+func (a *aggPick) update(gids []int, base int) {
+	for i, g := range gids {
+		if a.last || a.rows[g] < 0 {
+			a.rows[g] = base + i
+		}
+	}
+}
+
+// merge relies on partitions being merged in row order.
+func (a *aggPick) merge(src aggResolved, remap []int) {
+	for g, row := range src.(*aggPick).rows {
+		d := remap[g]
+		if row >= 0 && (a.last || a.rows[d] < 0) {
+			a.rows[d] = row
+		}
+	}
+}
+func (a *aggPick) fresh() aggResolved { return &aggPick{alias: a.alias, col: a.col, last: a.last} }
+func (a *aggPick) build() array.Column {
+	return renamed(a.col.Take(a.rows), a.alias)
+}
+
+type aggNullCount struct {
+	alias  string
+	col    array.Column
+	counts []int64
+}
+
+func (a *aggNullCount) grow(n int) { a.counts = growTo(a.counts, n, 0) }
+func (a *aggNullCount) update(gids []int, base int) {
+	for i, g := range gids {
+		if a.col.IsNull(base + i) {
+			a.counts[g]++
+		}
+	}
+}
+func (a *aggNullCount) merge(src aggResolved, remap []int) {
+	for g, c := range src.(*aggNullCount).counts {
+		a.counts[remap[g]] += c
+	}
+}
+func (a *aggNullCount) fresh() aggResolved { return &aggNullCount{alias: a.alias, col: a.col} }
+func (a *aggNullCount) build() array.Column {
+	return array.NewInt64ColumnOwned(a.alias, a.counts, array.NewBitmap(len(a.counts), true))
+}
+
+// aggExtremeRow implements Min/Max for non-numeric columns by tracking the
+// row holding the current extreme and gathering it at build time.
+type aggExtremeRow struct {
+	alias string
+	col   array.Column
+	cmp   func(a, b int) int
+	max   bool
+	rows  []int
+}
+
+func (a *aggExtremeRow) grow(n int) { a.rows = growTo(a.rows, n, -1) }
+func (a *aggExtremeRow) update(gids []int, base int) {
+	for i, g := range gids {
+		row := base + i
+		if !a.col.IsNull(row) {
+			a.offer(g, row)
+		}
+	}
+}
+func (a *aggExtremeRow) offer(g, row int) {
+	cur := a.rows[g]
+	if cur < 0 {
+		a.rows[g] = row
+		return
+	}
+	ord := a.cmp(row, cur)
+	if (a.max && ord > 0) || (!a.max && ord < 0) {
+		a.rows[g] = row
+	}
+}
+func (a *aggExtremeRow) merge(src aggResolved, remap []int) {
+	for g, row := range src.(*aggExtremeRow).rows {
+		if row >= 0 {
+			a.offer(remap[g], row)
+		}
+	}
+}
+func (a *aggExtremeRow) fresh() aggResolved {
+	return &aggExtremeRow{alias: a.alias, col: a.col, cmp: a.cmp, max: a.max}
+}
+func (a *aggExtremeRow) build() array.Column {
+	return renamed(a.col.Take(a.rows), a.alias)
+}
+
+func renamed(col array.Column, name string) array.Column {
+	out, err := array.WithName(col, name)
+	if err != nil {
+		// WithName only fails for unsupported column types or empty names,
+		// both of which are ruled out when the aggregation is resolved.
+		panic(err)
+	}
+	return out
+}
@@ -0,0 +1,344 @@
+package exec
+
+import (
+	"fmt"
+	"time"
+
+	"grizzly/internal/array"
+)
+
+type AsofStrategy uint8
+
+const (
+	// AsofBackward matches the last right row whose key is <= the left key.
+	AsofBackward AsofStrategy = iota
+	// AsofForward matches the first right row whose key is >= the left key.
+	AsofForward
+	// AsofNearest matches whichever of the two is closer, preferring the
+	// backward match on ties.
+	AsofNearest
+)
+
+func (s AsofStrategy) String() string {
+	switch s {
+	case AsofBackward:
+		return "backward"
+	case AsofForward:
+		return "forward"
+	case AsofNearest:
+		return "nearest"
+	default:
+		return "invalid"
+	}
+}
+
+type AsofOptions struct {
+	LeftOn  string
+	RightOn string
+	// By restricts matches to rows with equal values in these columns, which
+	// must exist in both frames.
+	By       []string
+	Strategy AsofStrategy
+	// Tolerance bounds the key distance of a match; nil means unbounded.
+	// Use an integer for integer and date (days) keys, a float64 for float
+	// and uint64 keys and a time.Duration for datetime and duration keys.
+	Tolerance any
+	// Suffix is appended to clashing right column names. Defaults to "_right".
+	Suffix string
+}
+
+// JoinAsof matches every left row with at most one right row by nearest key.
+//
+// Semantics:
+//   - Keys must be numeric or temporal with identical dtypes, and both
+//     frames must be sorted ascending by key (within each By group).
+//   - NULL keys never match; right rows with NULL keys are ignored.
+//   - Output has every left row in order, followed by the right columns
+//     except the By columns (and the right key when it has the left key's
+//     name); unmatched rows are NULL.
+func (df *DataFrame) JoinAsof(other *DataFrame, opts AsofOptions) (*DataFrame, error) {
+	if df == nil || other == nil {
+		return nil, fmt.Errorf("asof: nil dataframe")
+	}
+	if opts.Strategy > AsofNearest {
+		return nil, fmt.Errorf("asof: invalid strategy")
+	}
+	if opts.LeftOn == "" || opts.RightOn == "" {
+		return nil, fmt.Errorf("asof: LeftOn and RightOn are required")
+	}
+	lk, ok := df.Column(opts.LeftOn)
+	if !ok {
+		return nil, fmt.Errorf("asof: unknown left column %s", opts.LeftOn)
+	}
+	rk, ok := other.Column(opts.RightOn)
+	if !ok {
+		return nil, fmt.Errorf("asof: unknown right column %s", opts.RightOn)
+	}
+	if lk.DType() != rk.DType() {
+		return nil, fmt.Errorf("asof: key dtype mismatch %s vs %s", lk.DType(), rk.DType())
+	}
+	suffix := opts.Suffix
+	if suffix == "" {
+		suffix = "_right"
+	}
+
+	leftGroups, rightGroups, err := asofGroups(df, other, opts.By)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []int
+	if lv, ok := int64KeyAccessor(lk); ok {
+		rv, _ := int64KeyAccessor(rk)
+		var tol int64
+		hasTol := opts.Tolerance != nil
+		if hasTol {
+			if tol, err = asofIntTolerance(lk, opts.Tolerance); err != nil {
+				return nil, err
+			}
+		}
+		matches, err = asofMatch(asofSide[int64]{lk, lv, leftGroups}, asofSide[int64]{rk, rv, rightGroups}, compareInt64, opts.Strategy, tol, hasTol)
+	} else if l, ok := array.Float64Accessor(lk); ok {
+		r, _ := array.Float64Accessor(rk)
+		var tol float64
+		hasTol := opts.Tolerance != nil
+		if hasTol {
+			var ok bool
+			if tol, ok = literalFloat64(opts.Tolerance); !ok || tol < 0 {
+				return nil, fmt.Errorf("asof: invalid tolerance %v for %s key", opts.Tolerance, lk.DType())
+			}
+		}
+		matches, err = asofMatch(asofSide[float64]{lk, l, leftGroups}, asofSide[float64]{rk, r, rightGroups}, compareFloat64, opts.Strategy, tol, hasTol)
+	} else {
+		return nil, fmt.Errorf("asof: unsupported key dtype %s", lk.DType())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]array.Column, 0, len(df.columns)+len(other.columns))
+	names := make(map[string]struct{}, cap(out))
+	for _, c := range df.columns {
+		out = append(out, c)
+		names[c.Name()] = struct{}{}
+	}
+	skip := append([]string(nil), opts.By...)
+	if opts.RightOn == opts.LeftOn {
+		skip = append(skip, opts.RightOn)
+	}
+	out, err = appendRightColumns(out, names, other, skip, matches, suffix)
+	if err != nil {
+		return nil, err
+	}
+	return NewDataFrame(out...)
+}
+
+// asofGroups assigns By-group ids to the rows of both frames. Rows with a
+// NULL By value get -1; left rows whose group has no right rows get -1 too.
+// Without By columns every row is in group 0.
+func asofGroups(left, right *DataFrame, by []string) ([]int, []int, error) {
+	if len(by) == 0 {
+		return nil, nil, nil
+	}
+	leftCols := make([]array.Column, len(by))
+	rightCols := make([]array.Column, len(by))
+	for i, name := range by {
+		l, ok := left.Column(name)
+		if !ok {
+			return nil, nil, fmt.Errorf("asof: unknown left column %s", name)
+		}
+		r, ok := right.Column(name)
+		if !ok {
+			return nil, nil, fmt.Errorf("asof: unknown right column %s", name)
+		}
+		if l.DType() != r.DType() {
+			return nil, nil, fmt.Errorf("asof: by dtype mismatch for %s: %s vs %s", name, l.DType(), r.DType())
+		}
+		leftCols[i], rightCols[i] = l, r
+	}
+	leftEnc, err := newKeyEncoder(leftCols)
+	if err != nil {
+		return nil, nil, fmt.Errorf("asof: %w", err)
+	}
+	rightEnc, err := newKeyEncoder(rightCols)
+	if err != nil {
+		return nil, nil, fmt.Errorf("asof: %w", err)
+	}
+
+	ids := make(map[string]int, 256)
+	var buf []byte
+	rightGroups := make([]int, right.nrows)
+	for row := range rightGroups {
+		if rightEnc.hasNull(row) {
+			rightGroups[row] = -1
+			continue
+		}
+		buf = rightEnc.appendRow(buf[:0], row)
+		gi, ok := ids[string(buf)]
+		if !ok {
+			gi = len(ids)
+			ids[string(buf)] = gi
+		}
+		rightGroups[row] = gi
+	}
+	leftGroups := make([]int, left.nrows)
+	for row := range leftGroups {
+		leftGroups[row] = -1
+		if leftEnc.hasNull(row) {
+			continue
+		}
+		buf = leftEnc.appendRow(buf[:0], row)
+		if gi, ok := ids[string(buf)]; ok {
+			leftGroups[row] = gi
+		}
+	}
+	return leftGroups, rightGroups, nil
+}
+
+type asofSide[T int64 | float64] struct {
+	col    array.Column
+	value  func(int) T
+	groups []int
+}
+
+func (s asofSide[T]) group(row int) int {
+	if s.groups == nil {
+		return 0
+	}
+	return s.groups[row]
+}
+
+// asofMatch returns, for every left row, the matched right row or -1. Both
+// sides are walked once: left keys only grow within a group, so each group
+// keeps a cursor into its right rows that never moves backwards.
+func asofMatch[T int64 | float64](left, right asofSide[T], cmp func(a, b T, desc bool) int, strategy AsofStrategy, tol T, hasTol bool) ([]int, error) {
+	var rows [][]int
+	for row := 0; row < right.col.Len(); row++ {
+		g := right.group(row)
+		if g < 0 || right.col.IsNull(row) {
+			continue
+		}
+		for len(rows) <= g {
+			rows = append(rows, nil)
+		}
+		if n := len(rows[g]); n > 0 && cmp(right.value(rows[g][n-1]), right.value(row), false) > 0 {
+			return nil, fmt.Errorf("asof: right key %s must be sorted ascending", right.col.Name())
+		}
+		rows[g] = append(rows[g], row)
+	}
+
+	le := make([]int, len(rows)) // count of right keys <= the current left key
+	lt := make([]int, len(rows)) // count of right keys < the current left key
+	last := make([]int, len(rows))
+	for i := range last {
+		last[i] = -1
+	}
+	matches := make([]int, left.col.Len())
+	for row := range matches {
+		matches[row] = -1
+		g := left.group(row)
+		if g < 0 || g >= len(rows) || left.col.IsNull(row) {
+			continue
+		}
+		key := left.value(row)
+		if last[g] >= 0 && cmp(left.value(last[g]), key, false) > 0 {
+			return nil, fmt.Errorf("asof: left key %s must be sorted ascending", left.col.Name())
+		}
+		last[g] = row
+
+		rs := rows[g]
+		for le[g] < len(rs) && cmp(right.value(rs[le[g]]), key, false) <= 0 {
+			le[g]++
+		}
+		for lt[g] < len(rs) && cmp(right.value(rs[lt[g]]), key, false) < 0 {
+			lt[g]++
+		}
+		back, fwd := -1, -1
+		if strategy != AsofForward && le[g] > 0 {
+			back = rs[le[g]-1]
+		}
+		if strategy != AsofBackward && lt[g] < len(rs) {
+			fwd = rs[lt[g]]
+		}
+		match := back
+		if fwd >= 0 && (back < 0 || asofGapLess(key, right.value(fwd), right.value(back), key)) {
+			match = fwd
+		}
+		if match >= 0 && hasTol {
+			lo, hi := right.value(match), key
+			if match == fwd {
+				lo, hi = key, right.value(match)
+			}
+			var zero T
+			if asofGapLess(zero, tol, lo, hi) {
+				match = -1
+			}
+		}
+		matches[row] = match
+	}
+	return matches, nil
+}
+
+// asofGapLess reports whether hi1-lo1 < hi2-lo2, given lo1 <= hi1 and
+// lo2 <= hi2. Integer gaps are taken in uint64 so keys near the int64 limits,
+// such as MinInt64 sentinels, cannot overflow.
+func asofGapLess[T int64 | float64](lo1, hi1, lo2, hi2 T) bool {
+	if l1, ok := any(lo1).(int64); ok {
+		h1, l2, h2 := any(hi1).(int64), any(lo2).(int64), any(hi2).(int64)
+		return uint64(h1)-uint64(l1) < uint64(h2)-uint64(l2)
+	}
+	return hi1-lo1 < hi2-lo2
+}
+
+func asofIntTolerance(key array.Column, tol any) (int64, error) {
+	var v int64
+	ok := false
+	switch c := key.(type) {
+	case *array.DatetimeColumn:
+		if d, isDur := tol.(time.Duration); isDur {
+			v, ok = array.DurationToUnit(d, c.Unit()), true
+		}
+	case *array.DurationColumn:
+		if d, isDur := tol.(time.Duration); isDur {
+			v, ok = array.DurationToUnit(d, c.Unit()), true
+		}
+	case *array.DateColumn:
+		if d, isDur := tol.(time.Duration); isDur {
+			v, ok = int64(d/(24*time.Hour)), true
+		} else {
+			v, ok = literalInt64(tol)
+		}
+	default:
+		v, ok = literalInt64(tol)
+	}
+	if !ok || v < 0 {
+		return 0, fmt.Errorf("asof: invalid tolerance %v for %s key", tol, key.DType())
+	}
+	return v, nil
+}
+
+func literalInt64(v any) (int64, bool) {
+	switch x := v.(type) {
+	case int:
+		return int64(x), true
+	case int64:
+		return x, true
+	case int32:
+		return int64(x), true
+	default:
+		return 0, false
+	}
+}
+
+func literalFloat64(v any) (float64, bool) {
+	switch x := v.(type) {
+	case float64:
+		return x, true
+	case int:
+		return float64(x), true
+	case int64:
+		return float64(x), true
+	default:
+		return 0, false
+	}
+}
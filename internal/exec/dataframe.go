@@ -2,6 +2,7 @@ package exec
 
 import (
 	"bytes"
+	"cmp"
 	"container/heap"
 	"crypto/sha256"
 	"encoding/hex"
@@ -80,15 +81,16 @@ func (df *DataFrame) Select(names ...string) (*DataFrame, error) {
 	return NewDataFrame(cols...)
 }
 
+// Filter keeps the rows where e holds. The conjuncts of an And chain are
+// evaluated in order, each only on the rows the previous ones kept, so a
+// cheap selective conjunct first spares the others most of the work. A chain
+// with any conjunct that is not row-local (see expr.IsRowLocal) is evaluated
+// whole on every row instead.
 func (df *DataFrame) Filter(e expr.Expr) (*DataFrame, error) {
-	mask, err := e.Eval(df)
+	vals, err := df.filterMask(e)
 	if err != nil {
 		return nil, err
 	}
-	if len(mask.Data) != df.nrows {
-		return nil, fmt.Errorf("mask length mismatch")
-	}
-	vals := mask.Data
 	cols := make([]array.Column, len(df.columns))
 	for i := range df.columns {
 		cols[i] = df.columns[i].Filter(vals)
@@ -96,6 +98,78 @@ func (df *DataFrame) Filter(e expr.Expr) (*DataFrame, error) {
 	return NewDataFrame(cols...)
 }
 
+func (df *DataFrame) filterMask(e expr.Expr) ([]bool, error) {
+	parts := expr.Conjuncts(e)
+	if len(parts) == 1 || !expr.IsRowLocal(e) {
+		mask, err := e.Eval(df)
+		if err != nil {
+			return nil, err
+		}
+		if len(mask.Data) != df.nrows {
+			return nil, fmt.Errorf("mask length mismatch")
+		}
+		return mask.Data, nil
+	}
+	keep := make([]bool, df.nrows)
+	var rows []int // surviving rows; nil until the first conjunct ran
+	for _, part := range parts {
+		var f expr.Frame = df
+		if rows != nil {
+			f = &takeFrame{df: df, rows: rows}
+		}
+		// Later conjuncts still run on empty input so that they report the
+		// same errors as an unsplit evaluation.
+		mask, err := part.Eval(f)
+		if err != nil {
+			return nil, err
+		}
+		if len(mask.Data) != f.Height() {
+			return nil, fmt.Errorf("mask length mismatch")
+		}
+		next := make([]int, 0, len(mask.Data))
+		for i, ok := range mask.Data {
+			row := i
+			if rows != nil {
+				row = rows[i]
+			}
+			if ok {
+				next = append(next, row)
+			}
+		}
+		rows = next
+	}
+	for _, row := range rows {
+		keep[row] = true
+	}
+	return keep, nil
+}
+
+// takeFrame exposes a subset of a frame's rows to expression evaluation,
+// gathering each column only when an expression asks for it.
+type takeFrame struct {
+	df    *DataFrame
+	rows  []int
+	cache map[string]array.Column
+}
+
+func (f *takeFrame) Height() int { return len(f.rows) }
+
+func (f *takeFrame) Column(name string) (array.Column, bool) {
+	if col, ok := f.cache[name]; ok {
+		return col, true
+	}
+	col, ok := f.df.Column(name)
+	if !ok {
+		return nil, false
+	}
+	col = col.Take(f.rows)
+	if f.cache == nil {
+		f.cache = map[string]array.Column{}
+	}
+	f.cache[name] = col
+	return col, true
+}
+
 func (df *DataFrame) Slice(offset, length int) (*DataFrame, error) {
 	if offset < 0 {
 		return nil, fmt.Errorf("slice offset must be >= 0")
@@ -238,15 +312,40 @@ func (df *DataFrame) WithColumns(cols ...array.Column) (*DataFrame, error) {
 	return NewDataFrame(out...)
 }
 
-func (df *DataFrame) SortBy(column string, desc bool) (*DataFrame, error) {
-	c, ok := df.Column(column)
-	if !ok {
-		return nil, fmt.Errorf("unknown column %s", column)
+// WithColumnValues evaluates value expressions against df and adds or
+// replaces the resulting columns, named by expr.OutputName. All expressions
+// see the input frame, not each other's results.
+func (df *DataFrame) WithColumnValues(vals ...expr.Value) (*DataFrame, error) {
+	cols := make([]array.Column, len(vals))
+	for i, v := range vals {
+		col, err := df.EvalValue(v)
+		if err != nil {
+			return nil, err
+		}
+		cols[i] = col
 	}
-	order := make([]int, df.nrows)
-	for i := range order {
-		order[i] = i
+	return df.WithColumns(cols...)
+}
+
+// EvalValue evaluates v against df into a column named expr.OutputName(v).
+func (df *DataFrame) EvalValue(v expr.Value) (array.Column, error) {
+	col, err := v.EvalValue(df)
+	if err != nil {
+		return nil, err
+	}
+	if name := expr.OutputName(v); col.Name() != name {
+		return array.WithName(col, name)
 	}
+	return col, nil
+}
+
+// SortBy is a stable single-column SortKeys, NULLs last.
+func (df *DataFrame) SortBy(column string, desc bool) (*DataFrame, error) {
+	return df.SortKeys([]SortKey{{Col: column, Desc: desc}}, SortOptions{MaintainOrder: true})
+}
+
+// sortComparator orders rows of c by value, NULLs last in either direction.
+func sortComparator(c array.Column, desc bool) (func(a, b int) int, error) {
 	var cmp func(a, b int) int
 	switch col := c.(type) {
 	case *array.Int64Column:
@@ -269,25 +368,32 @@ func (df *DataFrame) SortBy(column string, desc bool) (*DataFrame, error) {
 			}
 			return compareNullAware(col.IsNull(a), col.IsNull(b), ord)
 		}
+	case *array.DateColumn:
+		cmp = func(a, b int) int {
+			return compareNullAware(col.IsNull(a), col.IsNull(b), compareInt64(int64(col.Value(a)), int64(col.Value(b)), desc))
+		}
+	case *array.DatetimeColumn:
+		cmp = func(a, b int) int {
+			return compareNullAware(col.IsNull(a), col.IsNull(b), compareInt64(col.Value(a), col.Value(b), desc))
+		}
+	case *array.DurationColumn:
+		cmp = func(a, b int) int {
+			return compareNullAware(col.IsNull(a), col.IsNull(b), compareInt64(col.Value(a), col.Value(b), desc))
+		}
 	default:
-		return nil, fmt.Errorf("unsupported sort dtype %s", c.DType())
-	}
-	if df.nrows >= parallelSortThreshold && runtime.GOMAXPROCS(0) > 1 {
-		parallelStableSort(order, cmp)
-	} else {
-		sort.SliceStable(order, func(i, j int) bool {
-			o := cmp(order[i], order[j])
-			if o == 0 {
-				return order[i] < order[j]
+		vc, ok := valueComparator(c)
+		if !ok {
+			return nil, fmt.Errorf("unsupported sort dtype %s", c.DType())
+		}
+		cmp = func(a, b int) int {
+			ord := vc(a, b)
+			if desc {
+				ord = -ord
 			}
-			return o < 0
-		})
-	}
-	cols := make([]array.Column, len(df.columns))
-	for i := range df.columns {
-		cols[i] = df.columns[i].Take(order)
+			return compareNullAware(c.IsNull(a), c.IsNull(b), ord)
+		}
 	}
-	return NewDataFrame(cols...)
+	return cmp, nil
 }
 
 func (df *DataFrame) ProjectionChecksum(maxCols int) string {
@@ -325,6 +431,8 @@ func (df *DataFrame) ProjectionChecksum(maxCols int) string {
 			case colKindUtf8:
 				s, e := v.s.ByteRange(i)
 				_, _ = h.Write(v.s.Bytes()[s:e])
+			case colKindTemporal, colKindNumeric:
+				hashWriteString(h, v.t.ValueString(i))
 			}
 		}
 		hashWriteByte(h, '\n')
@@ -334,45 +442,13 @@ func (df *DataFrame) ProjectionChecksum(maxCols int) string {
 
 func (df *DataFrame) MarshalRowsJSON() ([]byte, error) {
 	var buf bytes.Buffer
-	quotedKeys := make([]string, len(df.columns))
-	views := buildColumnViews(df.columns)
-	for i := range df.columns {
-		quotedKeys[i] = strconv.Quote(df.columns[i].Name())
-	}
+	enc := newRowEncoder(df)
 	buf.WriteByte('[')
 	for i := 0; i < df.nrows; i++ {
 		if i > 0 {
 			buf.WriteByte(',')
 		}
-		buf.WriteByte('{')
-		for j := range df.columns {
-			if j > 0 {
-				buf.WriteByte(',')
-			}
-			buf.WriteString(quotedKeys[j])
-			buf.WriteByte(':')
-			v := views[j]
-			if v.isNull(i) {
-				buf.WriteString("null")
-				continue
-			}
-			switch v.kind {
-			case colKindInt64:
-				buf.WriteString(strconv.FormatInt(v.i64.Value(i), 10))
-			case colKindFloat64:
-				buf.WriteString(strconv.FormatFloat(v.f64.Value(i), 'g', -1, 64))
-			case colKindBool:
-				if v.b.Value(i) {
-					buf.WriteString("true")
-				} else {
-					buf.WriteString("false")
-				}
-			case colKindUtf8:
-				s, e := v.s.ByteRange(i)
-				writeJSONStringEscapedBytes(&buf, v.s.Bytes()[s:e])
-			}
-		}
-		buf.WriteByte('}')
+		enc.encodeRow(&buf, i)
 	}
 	buf.WriteByte(']')
 	return buf.Bytes(), nil
@@ -395,6 +471,10 @@ const (
 	colKindFloat64
 	colKindBool
 	colKindUtf8
+	colKindTemporal
+	// colKindNumeric covers the narrow numeric columns, written through
+	// ValueString.
+	colKindNumeric
 )
 
 type utf8View interface {
@@ -409,6 +489,7 @@ type columnView struct {
 	f64  *array.Float64Column
 	b    *array.BoolColumn
 	s    *array.Utf8Column
+	t    array.Column
 }
 
 func (v columnView) isNull(i int) bool {
@@ -419,6 +500,8 @@ func (v columnView) isNull(i int) bool {
 		return v.f64.IsNull(i)
 	case colKindBool:
 		return v.b.IsNull(i)
+	case colKindTemporal, colKindNumeric:
+		return v.t.IsNull(i)
 	default:
 		return v.s.IsNull(i)
 	}
@@ -436,13 +519,30 @@ func buildColumnViews(cols []array.Column) []columnView {
 			out[i] = columnView{kind: colKindBool, b: c}
 		case *array.Utf8Column:
 			out[i] = columnView{kind: colKindUtf8, s: c}
+		case *array.DateColumn, *array.DatetimeColumn, *array.DurationColumn:
+			out[i] = columnView{kind: colKindTemporal, t: c}
 		default:
-			panic("unsupported column type")
+			if !array.IsNumeric(c.DType()) {
+				panic("unsupported column type")
+			}
+			out[i] = columnView{kind: colKindNumeric, t: c}
 		}
 	}
 	return out
 }
 
+// writeJSONTemporal encodes dates and datetimes as ISO-8601 strings and
+// durations as integer counts of their time unit.
+func writeJSONTemporal(buf *bytes.Buffer, col array.Column, i int) {
+	if d, ok := col.(*array.DurationColumn); ok {
+		buf.WriteString(strconv.FormatInt(d.Value(i), 10))
+		return
+	}
+	buf.WriteByte('"')
+	buf.WriteString(col.ValueString(i))
+	buf.WriteByte('"')
+}
+
 func writeJSONStringEscapedBytes(buf *bytes.Buffer, b []byte) {
 	buf.WriteByte('"')
 	for _, c := range b {
@@ -532,6 +632,36 @@ func compareBool(a, b bool, desc bool) int {
 	return 1
 }
 
+// valueComparator returns an ascending comparator over the non-null values
+// of col. NULL handling is left to the caller.
+func valueComparator(col array.Column) (func(a, b int) int, bool) {
+	switch c := col.(type) {
+	case *array.Int64Column:
+		return func(a, b int) int { return compareInt64(c.Value(a), c.Value(b), false) }, true
+	case *array.Float64Column:
+		return func(a, b int) int { return compareFloat64(c.Value(a), c.Value(b), false) }, true
+	case *array.BoolColumn:
+		return func(a, b int) int { return compareBool(c.Value(a), c.Value(b), false) }, true
+	case *array.Utf8Column:
+		return c.CompareRows, true
+	case *array.DateColumn:
+		return func(a, b int) int { return compareInt64(int64(c.Value(a)), int64(c.Value(b)), false) }, true
+	case *array.DatetimeColumn:
+		return func(a, b int) int { return compareInt64(c.Value(a), c.Value(b), false) }, true
+	case *array.DurationColumn:
+		return func(a, b int) int { return compareInt64(c.Value(a), c.Value(b), false) }, true
+	case *array.UInt64Column:
+		return func(a, b int) int { return cmp.Compare(c.Value(a), c.Value(b)) }, true
+	}
+	if get, ok := array.Int64Accessor(col); ok {
+		return func(a, b int) int { return compareInt64(get(a), get(b), false) }, true
+	}
+	if get, ok := array.Float64Accessor(col); ok {
+		return func(a, b int) int { return compareFloat64(get(a), get(b), false) }, true
+	}
+	return nil, false
+}
+
 type mergeRun struct {
 	data []int
 	pos  int
@@ -567,16 +697,27 @@ func (h *mergeHeap) Pop() any {
 	return v
 }
 
-func parallelStableSort(order []int, cmp func(a, b int) int) {
+// parallelSort orders the row indices in order by cmp. With stable, ties keep
+// their index order; otherwise their order is unspecified. When parallel is
+// set and enough workers are available, runs are sorted concurrently and
+// then merged.
+func parallelSort(order []int, cmp func(a, b int) int, stable, parallel bool) {
+	sortRun := func(data []int) {
+		if stable {
+			sort.SliceStable(data, func(i, j int) bool {
+				o := cmp(data[i], data[j])
+				if o == 0 {
+					return data[i] < data[j]
+				}
+				return o < 0
+			})
+			return
+		}
+		sort.Slice(data, func(i, j int) bool { return cmp(data[i], data[j]) < 0 })
+	}
 	workers := runtime.GOMAXPROCS(0)
-	if workers < 2 || len(order) < workers*4096 {
-		sort.SliceStable(order, func(i, j int) bool {
-			o := cmp(order[i], order[j])
-			if o == 0 {
-				return order[i] < order[j]
-			}
-			return o < 0
-		})
+	if !parallel || workers < 2 || len(order) < workers*4096 {
+		sortRun(order)
 		return
 	}
 	runs := make([]mergeRun, 0, workers)
@@ -594,15 +735,7 @@ func parallelStableSort(order []int, cmp func(a, b int) int) {
 		wg.Add(1)
 		go func(idx int) {
 			defer wg.Done()
-			sort.SliceStable(runs[idx].data, func(a, b int) bool {
-				x := runs[idx].data[a]
-				y := runs[idx].data[b]
-				o := cmp(x, y)
-				if o == 0 {
-					return x < y
-				}
-				return o < 0
-			})
+			sortRun(runs[idx].data)
 		}(i)
 	}
 	wg.Wait()
@@ -3,6 +3,8 @@ package exec
 import (
 	"fmt"
 	"math"
+	"runtime"
+	"sync"
 
 	"grizzly/internal/array"
 )
@@ -42,49 +44,82 @@ const (
 	AggMean
 	AggMin
 	AggMax
+	AggMedian
+	AggQuantile
+	AggStd
+	AggVar
+	AggNUnique
+	AggFirst
+	AggLast
+	AggNullCount
+)
+
+// QuantileInterpolation selects how AggQuantile picks a value that falls
+// between two observations. The zero value is QuantileNearest.
+type QuantileInterpolation uint8
+
+const (
+	QuantileNearest QuantileInterpolation = iota
+	QuantileLower
+	QuantileHigher
+	QuantileMidpoint
+	QuantileLinear
 )
 
 type AggSpec struct {
 	Col   string
 	Func  AggFunc
 	Alias string
+	// Quantile and Interpolation parameterize AggQuantile; Quantile must be in [0, 1].
+	Quantile      float64
+	Interpolation QuantileInterpolation
+	// DDof is the delta degrees of freedom for AggStd and AggVar (divisor
+	// n - DDof). nil means 1, the sample estimator.
+	DDof *int
 }
 
 // Count returns one row per group with a "count" column.
 //
-// Current limitations:
-// - Only a single int64 key column is supported.
-// - NULL keys form their own group.
+// Key columns may be of any numeric width, bool, utf8 or temporal; NULL keys
+// form their own group. Groups are emitted in first-seen order.
 func (g *GroupBy) Count() (*DataFrame, error) {
 	return g.Agg(AggSpec{Func: AggCount, Alias: "count"})
 }
 
 // Agg computes one or more aggregations per group.
 //
-// Current limitations:
-// - Only a single int64 key column is supported.
-// - Aggregations support int64 and float64 value columns.
-// - NULL keys form their own group.
-// - Aggregations ignore NULL values; if all values are NULL for a group, the result is NULL.
+// Semantics:
+//   - Any number of key columns of any numeric width, bool, utf8 or temporal.
+//   - NULL keys form their own group (per key combination).
+//   - Groups are emitted in first-seen order, key columns first.
+//   - Sum, Mean, Median, Quantile, Std and Var support every numeric value
+//     column. Sum and Mean widen integers other than uint64 to int64, and
+//     float32 and uint64 to float64; Median, Quantile, Std and Var always
+//     produce float64.
+//   - Min and Max additionally support bool, utf8 and temporal columns, and
+//     keep the dtype of narrow numeric columns.
+//   - NUnique, First, Last and NullCount support any key-compatible column.
+//   - Aggregations ignore NULL values; if all values are NULL for a group, the result is NULL.
+//     Exceptions: First/Last return the first/last row's value even if it is NULL,
+//     and NUnique counts NULL as one distinct value.
 func (g *GroupBy) Agg(specs ...AggSpec) (*DataFrame, error) {
 	if g == nil || g.df == nil {
 		return nil, fmt.Errorf("nil groupby")
 	}
-	if len(g.keys) != 1 {
-		return nil, fmt.Errorf("groupby: only single key supported")
-	}
 	if len(specs) == 0 {
 		return nil, fmt.Errorf("agg requires at least one spec")
 	}
 
-	keyName := g.keys[0]
-	keyCol, ok := g.df.Column(keyName)
-	if !ok {
-		return nil, fmt.Errorf("unknown column %s", keyName)
+	keyCols := make([]array.Column, len(g.keys))
+	for i, name := range g.keys {
+		c, ok := g.df.Column(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown column %s", name)
+		}
+		keyCols[i] = c
 	}
-	ic, ok := keyCol.(*array.Int64Column)
-	if !ok {
-		return nil, fmt.Errorf("groupby: only int64 key supported")
+	if _, err := newKeyEncoder(keyCols); err != nil {
+		return nil, fmt.Errorf("groupby: %w", err)
 	}
 
 	resolved, err := resolveAggs(g.df, specs)
@@ -92,91 +127,213 @@ func (g *GroupBy) Agg(specs ...AggSpec) (*DataFrame, error) {
 		return nil, err
 	}
 
-	// Preserve first-seen group order.
-	idx := make(map[int64]int, 256)
-	keys := make([]int64, 0, 256)
-	keyValid := make([]bool, 0, 256)
-	nullIdx := -1
+	parts, err := aggregatePartitions(keyCols, resolved, g.df.nrows)
+	if err != nil {
+		return nil, fmt.Errorf("groupby: %w", err)
+	}
+	global := mergePartitions(parts)
 
-	newGroup := func(key int64, valid bool) int {
-		gi := len(keys)
-		keys = append(keys, key)
-		keyValid = append(keyValid, valid)
-		for i := range resolved {
-			resolved[i].newGroup()
-		}
-		return gi
+	outCols := make([]array.Column, 0, len(keyCols)+len(resolved))
+	for i := range keyCols {
+		outCols = append(outCols, keyCols[i].Take(global.firstRows))
 	}
+	for i := range global.aggs {
+		outCols = append(outCols, global.aggs[i].build())
+	}
+	return NewDataFrame(outCols...)
+}
 
-	for row := 0; row < g.df.nrows; row++ {
-		var gi int
-		if ic.IsNull(row) {
-			if nullIdx < 0 {
-				nullIdx = newGroup(0, false)
+// aggResolved is the state of one aggregation. Each partition owns a fresh
+// copy; partitions are folded together with merge.
+type aggResolved interface {
+	// grow extends the state to n groups.
+	grow(n int)
+	// update folds rows base..base+len(gids)-1 into groups gids.
+	update(gids []int, base int)
+	// merge folds src into the receiver; src group g maps to group remap[g].
+	merge(src aggResolved, remap []int)
+	// fresh returns an empty state with the same configuration.
+	fresh() aggResolved
+	build() array.Column
+}
+
+const (
+	parallelAggThreshold = 100000
+	aggBlockRows         = 4096
+)
+
+// aggPartition aggregates a contiguous row range with its own hash table.
+// Group ids are local to the partition and assigned in first-seen order.
+type aggPartition struct {
+	groups    groupIndex
+	firstRows []int
+	aggs      []aggResolved
+}
+
+func (p *aggPartition) consume(start, end int) {
+	gids := make([]int, 0, aggBlockRows)
+	for base := start; base < end; base += aggBlockRows {
+		stop := min(base+aggBlockRows, end)
+		gids = gids[:0]
+		for row := base; row < stop; row++ {
+			gi, isNew := p.groups.lookup(row)
+			if isNew {
+				p.firstRows = append(p.firstRows, row)
 			}
-			gi = nullIdx
-		} else {
-			k := ic.Value(row)
-			var ok bool
-			gi, ok = idx[k]
-			if !ok {
-				gi = newGroup(k, true)
-				idx[k] = gi
+			gids = append(gids, gi)
+		}
+		for _, a := range p.aggs {
+			a.grow(len(p.firstRows))
+			a.update(gids, base)
+		}
+	}
+}
+
+// aggregatePartitions splits the rows into one contiguous range per worker
+// and aggregates each range independently. Small inputs use a single
+// partition.
+func aggregatePartitions(keyCols []array.Column, resolved []aggResolved, nrows int) ([]*aggPartition, error) {
+	workers := 1
+	if nrows >= parallelAggThreshold {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	parts := make([]*aggPartition, workers)
+	for w := range parts {
+		groups, err := newGroupIndex(keyCols)
+		if err != nil {
+			return nil, err
+		}
+		aggs := resolved
+		if w > 0 {
+			aggs = make([]aggResolved, len(resolved))
+			for i := range resolved {
+				aggs[i] = resolved[i].fresh()
 			}
 		}
-		for i := range resolved {
-			resolved[i].observe(gi, row)
+		parts[w] = &aggPartition{groups: groups, firstRows: make([]int, 0, 256), aggs: aggs}
+	}
+	if workers == 1 {
+		parts[0].consume(0, nrows)
+		return parts, nil
+	}
+
+	chunk := (nrows + workers - 1) / workers
+	var wg sync.WaitGroup
+	for w := range parts {
+		start := w * chunk
+		end := min(start+chunk, nrows)
+		wg.Add(1)
+		go func(p *aggPartition) {
+			defer wg.Done()
+			p.consume(start, end)
+		}(parts[w])
+	}
+	wg.Wait()
+	return parts, nil
+}
+
+// mergePartitions folds every partition into the first one. Partitions cover
+// ascending row ranges and are merged in order, so global groups keep their
+// first-seen order and First/Last see rows in table order. Group ids are
+// resolved sequentially; each aggregation then merges on its own goroutine.
+func mergePartitions(parts []*aggPartition) *aggPartition {
+	global := parts[0]
+	if len(parts) == 1 {
+		return global
+	}
+	remaps := make([][]int, len(parts))
+	for w := 1; w < len(parts); w++ {
+		p := parts[w]
+		remap := make([]int, len(p.firstRows))
+		for g, row := range p.firstRows {
+			gi, isNew := global.groups.lookup(row)
+			if isNew {
+				global.firstRows = append(global.firstRows, row)
+			}
+			remap[g] = gi
 		}
+		remaps[w] = remap
 	}
 
-	keyOut := array.NewInt64ColumnOwned(keyName, keys, array.NewBitmapFromBools(keyValid))
-	outCols := make([]array.Column, 0, 1+len(resolved))
-	outCols = append(outCols, keyOut)
-	for i := range resolved {
-		outCols = append(outCols, resolved[i].build())
+	var wg sync.WaitGroup
+	for i := range global.aggs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			dst := global.aggs[i]
+			dst.grow(len(global.firstRows))
+			for w := 1; w < len(parts); w++ {
+				dst.merge(parts[w].aggs[i], remaps[w])
+			}
+		}(i)
 	}
-	return NewDataFrame(outCols...)
+	wg.Wait()
+	return global
 }
 
-type aggResolved interface {
-	newGroup()
-	observe(groupIdx int, row int)
-	build() array.Column
+func growTo[T any](s []T, n int, fill T) []T {
+	for len(s) < n {
+		s = append(s, fill)
+	}
+	return s
+}
+
+// AggOutputName returns the output column name of spec: its Alias, or a
+// name derived from the column and function.
+func AggOutputName(s AggSpec) (string, error) {
+	if s.Alias != "" {
+		return s.Alias, nil
+	}
+	switch s.Func {
+	case AggCount:
+		return "count", nil
+	case AggSum:
+		return s.Col + "_sum", nil
+	case AggMean:
+		return s.Col + "_mean", nil
+	case AggMin:
+		return s.Col + "_min", nil
+	case AggMax:
+		return s.Col + "_max", nil
+	case AggMedian:
+		return s.Col + "_median", nil
+	case AggQuantile:
+		return s.Col + "_quantile", nil
+	case AggStd:
+		return s.Col + "_std", nil
+	case AggVar:
+		return s.Col + "_var", nil
+	case AggNUnique:
+		return s.Col + "_n_unique", nil
+	case AggFirst:
+		return s.Col + "_first", nil
+	case AggLast:
+		return s.Col + "_last", nil
+	case AggNullCount:
+		return s.Col + "_null_count", nil
+	default:
+		return "", fmt.Errorf("unknown agg func")
+	}
 }
 
 func resolveAggs(df *DataFrame, specs []AggSpec) ([]aggResolved, error) {
 	seenAlias := map[string]struct{}{}
 	out := make([]aggResolved, 0, len(specs))
 	for _, s := range specs {
-		alias := s.Alias
-		if alias == "" {
-			switch s.Func {
-			case AggCount:
-				alias = "count"
-			case AggSum:
-				alias = s.Col + "_sum"
-			case AggMean:
-				alias = s.Col + "_mean"
-			case AggMin:
-				alias = s.Col + "_min"
-			case AggMax:
-				alias = s.Col + "_max"
-			default:
-				return nil, fmt.Errorf("unknown agg func")
-			}
+		alias, err := AggOutputName(s)
+		if err != nil {
+			return nil, err
 		}
 		if _, ok := seenAlias[alias]; ok {
 			return nil, fmt.Errorf("duplicate agg output %s", alias)
 		}
 		seenAlias[alias] = struct{}{}
 
-		switch s.Func {
-		case AggCount:
+		if s.Func == AggCount {
 			out = append(out, &aggCount{alias: alias})
 			continue
-		case AggSum, AggMean, AggMin, AggMax:
-			// continue below
-		default:
+		}
+		if s.Func <= AggInvalid || s.Func > AggNullCount {
 			return nil, fmt.Errorf("invalid agg func")
 		}
 		if s.Col == "" {
@@ -186,6 +343,53 @@ func resolveAggs(df *DataFrame, specs []AggSpec) ([]aggResolved, error) {
 		if !ok {
 			return nil, fmt.Errorf("unknown column %s", s.Col)
 		}
+
+		switch s.Func {
+		case AggMedian, AggQuantile, AggStd, AggVar:
+			value, ok := array.Float64Accessor(col)
+			if !ok {
+				return nil, fmt.Errorf("unsupported agg dtype %s", col.DType())
+			}
+			switch s.Func {
+			case AggMedian:
+				out = append(out, &aggQuantile{alias: alias, col: col, value: value, q: 0.5, interp: QuantileLinear})
+			case AggQuantile:
+				if math.IsNaN(s.Quantile) || s.Quantile < 0 || s.Quantile > 1 {
+					return nil, fmt.Errorf("quantile must be in [0, 1]")
+				}
+				out = append(out, &aggQuantile{alias: alias, col: col, value: value, q: s.Quantile, interp: s.Interpolation})
+			default:
+				ddof := 1
+				if s.DDof != nil {
+					ddof = *s.DDof
+				}
+				if ddof < 0 {
+					return nil, fmt.Errorf("ddof must be >= 0")
+				}
+				out = append(out, &aggVariance{alias: alias, col: col, value: value, ddof: ddof, std: s.Func == AggStd})
+			}
+			continue
+		case AggNUnique:
+			enc, err := newKeyEncoder([]array.Column{col})
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, &aggNUnique{alias: alias, enc: enc, seen: make(map[string]struct{}, 256)})
+			continue
+		case AggFirst:
+			out = append(out, &aggPick{alias: alias, col: col})
+			continue
+		case AggLast:
+			out = append(out, &aggPick{alias: alias, col: col, last: true})
+			continue
+		case AggNullCount:
+			out = append(out, &aggNullCount{alias: alias, col: col})
+			continue
+		}
+
+		if s.Func == AggSum || s.Func == AggMean {
+			col = widenNumeric(col)
+		}
 		switch c := col.(type) {
 		case *array.Int64Column:
 			switch s.Func {
@@ -210,21 +414,52 @@ func resolveAggs(df *DataFrame, specs []AggSpec) ([]aggResolved, error) {
 				out = append(out, &aggFloat64Max{alias: alias, col: c})
 			}
 		default:
-			return nil, fmt.Errorf("unsupported agg dtype")
+			cmp, ok := valueComparator(col)
+			if !ok || (s.Func != AggMin && s.Func != AggMax) {
+				return nil, fmt.Errorf("unsupported agg dtype %s", col.DType())
+			}
+			out = append(out, &aggExtremeRow{alias: alias, col: col, cmp: cmp, max: s.Func == AggMax})
 		}
 	}
 	return out, nil
 }
 
+// widenNumeric casts narrow numeric columns to int64 or float64 for the sum
+// and mean kernels, which are specialized on those types. uint64 widens to
+// float64 because its values may not fit int64.
+func widenNumeric(col array.Column) array.Column {
+	dt := col.DType()
+	to := array.Int(64)
+	switch {
+	case !array.IsNumeric(dt) || dt == array.Int(64) || dt == array.Float(64):
+		return col
+	case dt.Kind == array.KindFloat || dt == array.UInt(64):
+		to = array.Float(64)
+	}
+	out, err := array.Cast(col, to)
+	if err != nil {
+		return col
+	}
+	return out
+}
+
 type aggCount struct {
 	alias  string
 	counts []int64
 }
 
-func (a *aggCount) newGroup() { a.counts = append(a.counts, 0) }
-func (a *aggCount) observe(groupIdx int, _ int) {
-	a.counts[groupIdx]++
+func (a *aggCount) grow(n int) { a.counts = growTo(a.counts, n, 0) }
+func (a *aggCount) update(gids []int, _ int) {
+	for _, g := range gids {
+		a.counts[g]++
+	}
+}
+func (a *aggCount) merge(src aggResolved, remap []int) {
+	for g, c := range src.(*aggCount).counts {
+		a.counts[remap[g]] += c
+	}
 }
+func (a *aggCount) fresh() aggResolved { return &aggCount{alias: a.alias} }
 func (a *aggCount) build() array.Column {
 	return array.NewInt64ColumnOwned(a.alias, a.counts, array.NewBitmap(len(a.counts), true))
 }
@@ -236,17 +471,30 @@ type aggInt64Sum struct {
 	valid []bool
 }
 
-func (a *aggInt64Sum) newGroup() {
-	a.sums = append(a.sums, 0)
-	a.valid = append(a.valid, false)
+func (a *aggInt64Sum) grow(n int) {
+	a.sums = growTo(a.sums, n, 0)
+	a.valid = growTo(a.valid, n, false)
 }
-func (a *aggInt64Sum) observe(groupIdx int, row int) {
-	if a.col.IsNull(row) {
-		return
+func (a *aggInt64Sum) update(gids []int, base int) {
+	for i, g := range gids {
+		row := base + i
+		if a.col.IsNull(row) {
+			continue
+		}
+		a.sums[g] += a.col.Value(row)
+		a.valid[g] = true
+	}
+}
+func (a *aggInt64Sum) merge(src aggResolved, remap []int) {
+	s := src.(*aggInt64Sum)
+	for g, d := range remap {
+		if s.valid[g] {
+			a.sums[d] += s.sums[g]
+			a.valid[d] = true
+		}
 	}
-	a.sums[groupIdx] += a.col.Value(row)
-	a.valid[groupIdx] = true
 }
+func (a *aggInt64Sum) fresh() aggResolved { return &aggInt64Sum{alias: a.alias, col: a.col} }
 func (a *aggInt64Sum) build() array.Column {
 	return array.NewInt64ColumnOwned(a.alias, a.sums, array.NewBitmapFromBools(a.valid))
 }
@@ -258,17 +506,30 @@ type aggFloat64Sum struct {
 	valid []bool
 }
 
-func (a *aggFloat64Sum) newGroup() {
-	a.sums = append(a.sums, 0)
-	a.valid = append(a.valid, false)
+func (a *aggFloat64Sum) grow(n int) {
+	a.sums = growTo(a.sums, n, 0)
+	a.valid = growTo(a.valid, n, false)
 }
-func (a *aggFloat64Sum) observe(groupIdx int, row int) {
-	if a.col.IsNull(row) {
-		return
+func (a *aggFloat64Sum) update(gids []int, base int) {
+	for i, g := range gids {
+		row := base + i
+		if a.col.IsNull(row) {
+			continue
+		}
+		a.sums[g] += a.col.Value(row)
+		a.valid[g] = true
 	}
-	a.sums[groupIdx] += a.col.Value(row)
-	a.valid[groupIdx] = true
 }
+func (a *aggFloat64Sum) merge(src aggResolved, remap []int) {
+	s := src.(*aggFloat64Sum)
+	for g, d := range remap {
+		if s.valid[g] {
+			a.sums[d] += s.sums[g]
+			a.valid[d] = true
+		}
+	}
+}
+func (a *aggFloat64Sum) fresh() aggResolved { return &aggFloat64Sum{alias: a.alias, col: a.col} }
 func (a *aggFloat64Sum) build() array.Column {
 	return array.NewFloat64ColumnOwned(a.alias, a.sums, array.NewBitmapFromBools(a.valid))
 }
@@ -278,32 +539,32 @@ type aggInt64Mean struct {
 	col   *array.Int64Column
 	sum   []float64
 	count []int64
-	valid []bool
 }
 
-func (a *aggInt64Mean) newGroup() {
-	a.sum = append(a.sum, 0)
-	a.count = append(a.count, 0)
-	a.valid = append(a.valid, false)
+func (a *aggInt64Mean) grow(n int) {
+	a.sum = growTo(a.sum, n, 0)
+	a.count = growTo(a.count, n, 0)
 }
-func (a *aggInt64Mean) observe(groupIdx int, row int) {
-	if a.col.IsNull(row) {
-		return
-	}
-	a.sum[groupIdx] += float64(a.col.Value(row))
-	a.count[groupIdx]++
-	a.valid[groupIdx] = true
-}
-func (a *aggInt64Mean) build() array.Column {
-	out := make([]float64, len(a.sum))
-	for i := range out {
-		if a.count[i] == 0 {
-			out[i] = 0
+func (a *aggInt64Mean) update(gids []int, base int) {
+	for i, g := range gids {
+		row := base + i
+		if a.col.IsNull(row) {
 			continue
 		}
-		out[i] = a.sum[i] / float64(a.count[i])
+		a.sum[g] += float64(a.col.Value(row))
+		a.count[g]++
 	}
-	return array.NewFloat64ColumnOwned(a.alias, out, array.NewBitmapFromBools(a.valid))
+}
+func (a *aggInt64Mean) merge(src aggResolved, remap []int) {
+	s := src.(*aggInt64Mean)
+	for g, d := range remap {
+		a.sum[d] += s.sum[g]
+		a.count[d] += s.count[g]
+	}
+}
+func (a *aggInt64Mean) fresh() aggResolved { return &aggInt64Mean{alias: a.alias, col: a.col} }
+func (a *aggInt64Mean) build() array.Column {
+	return buildMean(a.alias, a.sum, a.count)
 }
 
 type aggFloat64Mean struct {
@@ -311,32 +572,45 @@ type aggFloat64Mean struct {
 	col   *array.Float64Column
 	sum   []float64
 	count []int64
-	valid []bool
 }
 
-func (a *aggFloat64Mean) newGroup() {
-	a.sum = append(a.sum, 0)
-	a.count = append(a.count, 0)
-	a.valid = append(a.valid, false)
+func (a *aggFloat64Mean) grow(n int) {
+	a.sum = growTo(a.sum, n, 0)
+	a.count = growTo(a.count, n, 0)
+}
+func (a *aggFloat64Mean) update(gids []int, base int) {
+	for i, g := range gids {
+		row := base + i
+		if a.col.IsNull(row) {
+			continue
+		}
+		a.sum[g] += a.col.Value(row)
+		a.count[g]++
+	}
 }
-func (a *aggFloat64Mean) observe(groupIdx int, row int) {
-	if a.col.IsNull(row) {
-		return
+func (a *aggFloat64Mean) merge(src aggResolved, remap []int) {
+	s := src.(*aggFloat64Mean)
+	for g, d := range remap {
+		a.sum[d] += s.sum[g]
+		a.count[d] += s.count[g]
 	}
-	a.sum[groupIdx] += a.col.Value(row)
-	a.count[groupIdx]++
-	a.valid[groupIdx] = true
 }
+func (a *aggFloat64Mean) fresh() aggResolved { return &aggFloat64Mean{alias: a.alias, col: a.col} }
 func (a *aggFloat64Mean) build() array.Column {
-	out := make([]float64, len(a.sum))
+	return buildMean(a.alias, a.sum, a.count)
+}
+
+func buildMean(alias string, sum []float64, count []int64) array.Column {
+	out := make([]float64, len(sum))
+	valid := make([]bool, len(sum))
 	for i := range out {
-		if a.count[i] == 0 {
-			out[i] = 0
+		if count[i] == 0 {
 			continue
 		}
-		out[i] = a.sum[i] / float64(a.count[i])
+		out[i] = sum[i] / float64(count[i])
+		valid[i] = true
 	}
-	return array.NewFloat64ColumnOwned(a.alias, out, array.NewBitmapFromBools(a.valid))
+	return array.NewFloat64ColumnOwned(alias, out, array.NewBitmapFromBools(valid))
 }
 
 type aggInt64Min struct {
@@ -346,20 +620,33 @@ type aggInt64Min struct {
 	valid []bool
 }
 
-func (a *aggInt64Min) newGroup() {
-	a.mins = append(a.mins, maxInt64)
-	a.valid = append(a.valid, false)
+func (a *aggInt64Min) grow(n int) {
+	a.mins = growTo(a.mins, n, maxInt64)
+	a.valid = growTo(a.valid, n, false)
 }
-func (a *aggInt64Min) observe(groupIdx int, row int) {
-	if a.col.IsNull(row) {
-		return
+func (a *aggInt64Min) update(gids []int, base int) {
+	for i, g := range gids {
+		row := base + i
+		if a.col.IsNull(row) {
+			continue
+		}
+		v := a.col.Value(row)
+		if !a.valid[g] || v < a.mins[g] {
+			a.mins[g] = v
+			a.valid[g] = true
+		}
 	}
-	v := a.col.Value(row)
-	if !a.valid[groupIdx] || v < a.mins[groupIdx] {
-		a.mins[groupIdx] = v
-		a.valid[groupIdx] = true
+}
+func (a *aggInt64Min) merge(src aggResolved, remap []int) {
+	s := src.(*aggInt64Min)
+	for g, d := range remap {
+		if s.valid[g] && (!a.valid[d] || s.mins[g] < a.mins[d]) {
+			a.mins[d] = s.mins[g]
+			a.valid[d] = true
+		}
 	}
 }
+func (a *aggInt64Min) fresh() aggResolved { return &aggInt64Min{alias: a.alias, col: a.col} }
 func (a *aggInt64Min) build() array.Column {
 	// Replace sentinel values for invalid groups with 0 to keep output stable.
 	for i := range a.mins {
@@ -377,20 +664,33 @@ type aggInt64Max struct {
 	valid []bool
 }
 
-func (a *aggInt64Max) newGroup() {
-	a.maxs = append(a.maxs, minInt64)
-	a.valid = append(a.valid, false)
+func (a *aggInt64Max) grow(n int) {
+	a.maxs = growTo(a.maxs, n, minInt64)
+	a.valid = growTo(a.valid, n, false)
 }
-func (a *aggInt64Max) observe(groupIdx int, row int) {
-	if a.col.IsNull(row) {
-		return
+func (a *aggInt64Max) update(gids []int, base int) {
+	for i, g := range gids {
+		row := base + i
+		if a.col.IsNull(row) {
+			continue
+		}
+		v := a.col.Value(row)
+		if !a.valid[g] || v > a.maxs[g] {
+			a.maxs[g] = v
+			a.valid[g] = true
+		}
 	}
-	v := a.col.Value(row)
-	if !a.valid[groupIdx] || v > a.maxs[groupIdx] {
-		a.maxs[groupIdx] = v
-		a.valid[groupIdx] = true
+}
+func (a *aggInt64Max) merge(src aggResolved, remap []int) {
+	s := src.(*aggInt64Max)
+	for g, d := range remap {
+		if s.valid[g] && (!a.valid[d] || s.maxs[g] > a.maxs[d]) {
+			a.maxs[d] = s.maxs[g]
+			a.valid[d] = true
+		}
 	}
 }
+func (a *aggInt64Max) fresh() aggResolved { return &aggInt64Max{alias: a.alias, col: a.col} }
 func (a *aggInt64Max) build() array.Column {
 	for i := range a.maxs {
 		if !a.valid[i] {
@@ -407,20 +707,33 @@ type aggFloat64Min struct {
 	valid []bool
 }
 
-func (a *aggFloat64Min) newGroup() {
-	a.mins = append(a.mins, math.Inf(1))
-	a.valid = append(a.valid, false)
+func (a *aggFloat64Min) grow(n int) {
+	a.mins = growTo(a.mins, n, math.Inf(1))
+	a.valid = growTo(a.valid, n, false)
 }
-func (a *aggFloat64Min) observe(groupIdx int, row int) {
-	if a.col.IsNull(row) {
-		return
+func (a *aggFloat64Min) update(gids []int, base int) {
+	for i, g := range gids {
+		row := base + i
+		if a.col.IsNull(row) {
+			continue
+		}
+		v := a.col.Value(row)
+		if !a.valid[g] || v < a.mins[g] {
+			a.mins[g] = v
+			a.valid[g] = true
+		}
 	}
-	v := a.col.Value(row)
-	if !a.valid[groupIdx] || v < a.mins[groupIdx] {
-		a.mins[groupIdx] = v
-		a.valid[groupIdx] = true
+}
+func (a *aggFloat64Min) merge(src aggResolved, remap []int) {
+	s := src.(*aggFloat64Min)
+	for g, d := range remap {
+		if s.valid[g] && (!a.valid[d] || s.mins[g] < a.mins[d]) {
+			a.mins[d] = s.mins[g]
+			a.valid[d] = true
+		}
 	}
 }
+func (a *aggFloat64Min) fresh() aggResolved { return &aggFloat64Min{alias: a.alias, col: a.col} }
 func (a *aggFloat64Min) build() array.Column {
 	for i := range a.mins {
 		if !a.valid[i] {
@@ -437,20 +750,33 @@ type aggFloat64Max struct {
 	valid []bool
 }
 
-func (a *aggFloat64Max) newGroup() {
-	a.maxs = append(a.maxs, math.Inf(-1))
-	a.valid = append(a.valid, false)
+func (a *aggFloat64Max) grow(n int) {
+	a.maxs = growTo(a.maxs, n, math.Inf(-1))
+	a.valid = growTo(a.valid, n, false)
 }
-func (a *aggFloat64Max) observe(groupIdx int, row int) {
-	if a.col.IsNull(row) {
-		return
+func (a *aggFloat64Max) update(gids []int, base int) {
+	for i, g := range gids {
+		row := base + i
+		if a.col.IsNull(row) {
+			continue
+		}
+		v := a.col.Value(row)
+		if !a.valid[g] || v > a.maxs[g] {
+			a.maxs[g] = v
+			a.valid[g] = true
+		}
 	}
-	v := a.col.Value(row)
-	if !a.valid[groupIdx] || v > a.maxs[groupIdx] {
-		a.maxs[groupIdx] = v
-		a.valid[groupIdx] = true
+}
+func (a *aggFloat64Max) merge(src aggResolved, remap []int) {
+	s := src.(*aggFloat64Max)
+	for g, d := range remap {
+		if s.valid[g] && (!a.valid[d] || s.maxs[g] > a.maxs[d]) {
+			a.maxs[d] = s.maxs[g]
+			a.valid[d] = true
+		}
 	}
 }
+func (a *aggFloat64Max) fresh() aggResolved { return &aggFloat64Max{alias: a.alias, col: a.col} }
 func (a *aggFloat64Max) build() array.Column {
 	for i := range a.maxs {
 		if !a.valid[i] {
@@ -0,0 +1,191 @@
+package exec
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"grizzly/internal/array"
+)
+
+// keyEncoder serializes the key columns of one row into a byte string that can
+// be used as a hash-map key. Every value is prefixed with a validity byte, so
+// NULLs compare equal to each other and never to a value. Variable-width
+// values are length-prefixed to keep concatenated keys unambiguous.
+type keyEncoder struct {
+	cols []array.Column
+}
+
+func newKeyEncoder(cols []array.Column) (*keyEncoder, error) {
+	for _, c := range cols {
+		if !hashableKey(c) {
+			return nil, fmt.Errorf("unsupported key dtype %s for column %s", c.DType(), c.Name())
+		}
+	}
+	return &keyEncoder{cols: cols}, nil
+}
+
+func hashableKey(c array.Column) bool {
+	switch c.(type) {
+	case *array.Int64Column, *array.Float64Column, *array.BoolColumn, *array.Utf8Column,
+		*array.DateColumn, *array.DatetimeColumn, *array.DurationColumn,
+		*array.Int32Column, *array.Int16Column, *array.Int8Column,
+		*array.UInt64Column, *array.UInt32Column, *array.UInt16Column, *array.UInt8Column,
+		*array.Float32Column:
+		return true
+	default:
+		return false
+	}
+}
+
+// hasNull reports whether any key column is NULL at row.
+func (e *keyEncoder) hasNull(row int) bool {
+	for _, c := range e.cols {
+		if c.IsNull(row) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *keyEncoder) appendRow(dst []byte, row int) []byte {
+	for _, col := range e.cols {
+		if col.IsNull(row) {
+			dst = append(dst, 0)
+			continue
+		}
+		dst = append(dst, 1)
+		switch c := col.(type) {
+		case *array.Int64Column:
+			dst = binary.LittleEndian.AppendUint64(dst, uint64(c.Value(row)))
+		case *array.Float64Column:
+			dst = binary.LittleEndian.AppendUint64(dst, canonicalFloatBits(c.Value(row)))
+		case *array.BoolColumn:
+			if c.Value(row) {
+				dst = append(dst, 1)
+			} else {
+				dst = append(dst, 0)
+			}
+		case *array.Utf8Column:
+			s, end := c.ByteRange(row)
+			dst = binary.LittleEndian.AppendUint32(dst, uint32(end-s))
+			dst = append(dst, c.Bytes()[s:end]...)
+		case *array.DateColumn:
+			dst = binary.LittleEndian.AppendUint32(dst, uint32(c.Value(row)))
+		case *array.DatetimeColumn:
+			dst = binary.LittleEndian.AppendUint64(dst, uint64(c.Value(row)))
+		case *array.DurationColumn:
+			dst = binary.LittleEndian.AppendUint64(dst, uint64(c.Value(row)))
+		case *array.Int32Column:
+			dst = binary.LittleEndian.AppendUint32(dst, uint32(c.Value(row)))
+		case *array.Int16Column:
+			dst = binary.LittleEndian.AppendUint16(dst, uint16(c.Value(row)))
+		case *array.Int8Column:
+			dst = append(dst, byte(c.Value(row)))
+		case *array.UInt64Column:
+			dst = binary.LittleEndian.AppendUint64(dst, c.Value(row))
+		case *array.UInt32Column:
+			dst = binary.LittleEndian.AppendUint32(dst, c.Value(row))
+		case *array.UInt16Column:
+			dst = binary.LittleEndian.AppendUint16(dst, c.Value(row))
+		case *array.UInt8Column:
+			dst = append(dst, c.Value(row))
+		case *array.Float32Column:
+			dst = binary.LittleEndian.AppendUint64(dst, canonicalFloatBits(float64(c.Value(row))))
+		}
+	}
+	return dst
+}
+
+// canonicalFloatBits maps -0 onto +0 and every NaN onto one payload so that
+// equal-looking floats hash to the same key.
+func canonicalFloatBits(v float64) uint64 {
+	if v == 0 {
+		return 0
+	}
+	if math.IsNaN(v) {
+		return math.Float64bits(math.NaN())
+	}
+	return math.Float64bits(v)
+}
+
+// int64KeyAccessor returns a raw int64 view for single-column keys that are
+// integer-backed, enabling a map[int64] fast path. The view preserves order,
+// so uint64 keys are left to the byte encoder.
+func int64KeyAccessor(col array.Column) (func(int) int64, bool) {
+	if get, ok := array.Int64Accessor(col); ok {
+		return get, true
+	}
+	switch c := col.(type) {
+	case *array.DatetimeColumn:
+		return c.Value, true
+	case *array.DurationColumn:
+		return c.Value, true
+	case *array.DateColumn:
+		return func(i int) int64 { return int64(c.Value(i)) }, true
+	default:
+		return nil, false
+	}
+}
+
+// groupIndex assigns dense group ids to rows in first-seen order.
+type groupIndex interface {
+	// lookup returns the group id of row and whether the group is new.
+	lookup(row int) (int, bool)
+}
+
+func newGroupIndex(keyCols []array.Column) (groupIndex, error) {
+	if len(keyCols) == 1 {
+		if value, ok := int64KeyAccessor(keyCols[0]); ok {
+			return &int64GroupIndex{col: keyCols[0], value: value, idx: make(map[int64]int, 256), nullIdx: -1}, nil
+		}
+	}
+	enc, err := newKeyEncoder(keyCols)
+	if err != nil {
+		return nil, err
+	}
+	return &encodedGroupIndex{enc: enc, idx: make(map[string]int, 256)}, nil
+}
+
+type int64GroupIndex struct {
+	col     array.Column
+	value   func(int) int64
+	idx     map[int64]int
+	nullIdx int
+	n       int
+}
+
+func (g *int64GroupIndex) lookup(row int) (int, bool) {
+	if g.col.IsNull(row) {
+		if g.nullIdx < 0 {
+			g.nullIdx = g.n
+			g.n++
+			return g.nullIdx, true
+		}
+		return g.nullIdx, false
+	}
+	k := g.value(row)
+	if gi, ok := g.idx[k]; ok {
+		return gi, false
+	}
+	gi := g.n
+	g.idx[k] = gi
+	g.n++
+	return gi, true
+}
+
+type encodedGroupIndex struct {
+	enc *keyEncoder
+	idx map[string]int
+	buf []byte
+}
+
+func (g *encodedGroupIndex) lookup(row int) (int, bool) {
+	g.buf = g.enc.appendRow(g.buf[:0], row)
+	if gi, ok := g.idx[string(g.buf)]; ok {
+		return gi, false
+	}
+	gi := len(g.idx)
+	g.idx[string(g.buf)] = gi
+	return gi, true
+}
@@ -0,0 +1,414 @@
+package exec
+
+import (
+	"fmt"
+	"hash/maphash"
+	"runtime"
+	"sync"
+
+	"grizzly/internal/array"
+)
+
+type JoinType uint8
+
+const (
+	JoinInvalid JoinType = iota
+	JoinInner
+	JoinLeft
+	JoinRight
+	JoinFull
+	JoinSemi
+	JoinAnti
+)
+
+func (t JoinType) String() string {
+	switch t {
+	case JoinInner:
+		return "inner"
+	case JoinLeft:
+		return "left"
+	case JoinRight:
+		return "right"
+	case JoinFull:
+		return "full"
+	case JoinSemi:
+		return "semi"
+	case JoinAnti:
+		return "anti"
+	default:
+		return "invalid"
+	}
+}
+
+type JoinOptions struct {
+	// On names key columns present in both frames. Use LeftOn/RightOn when the
+	// names differ; the two lists pair up by position.
+	On      []string
+	LeftOn  []string
+	RightOn []string
+	How     JoinType
+	// Suffix is appended to right columns whose name clashes with an output
+	// column. Defaults to "_right".
+	Suffix string
+	// JoinNulls makes NULL keys match each other. By default a row with any
+	// NULL key never matches.
+	JoinNulls bool
+}
+
+const parallelJoinThreshold = 100000
+
+// Join combines df with other on equal key values.
+//
+// Semantics:
+//   - Keys may be any hashable dtype; paired keys must have identical dtypes.
+//   - Output rows follow the left frame's order (the right frame's for
+//     JoinRight), with multiple matches in the other frame's row order. Full
+//     joins append unmatched right rows at the end.
+//   - Output columns are the left columns followed by the right non-key
+//     columns. Semi and anti joins return only the left columns.
+//   - Right and full joins fill the left key columns from the right keys for
+//     rows without a left match.
+func (df *DataFrame) Join(other *DataFrame, opts JoinOptions) (*DataFrame, error) {
+	if df == nil || other == nil {
+		return nil, fmt.Errorf("join: nil dataframe")
+	}
+	if opts.How <= JoinInvalid || opts.How > JoinAnti {
+		return nil, fmt.Errorf("join: invalid join type")
+	}
+	leftOn, rightOn := opts.LeftOn, opts.RightOn
+	if len(opts.On) > 0 {
+		if len(leftOn) > 0 || len(rightOn) > 0 {
+			return nil, fmt.Errorf("join: use either On or LeftOn/RightOn")
+		}
+		leftOn, rightOn = opts.On, opts.On
+	}
+	if len(leftOn) == 0 || len(leftOn) != len(rightOn) {
+		return nil, fmt.Errorf("join: key lists must be non-empty and of equal length")
+	}
+	suffix := opts.Suffix
+	if suffix == "" {
+		suffix = "_right"
+	}
+
+	leftKeys := make([]array.Column, len(leftOn))
+	rightKeys := make([]array.Column, len(rightOn))
+	for i := range leftOn {
+		l, ok := df.Column(leftOn[i])
+		if !ok {
+			return nil, fmt.Errorf("join: unknown left column %s", leftOn[i])
+		}
+		r, ok := other.Column(rightOn[i])
+		if !ok {
+			return nil, fmt.Errorf("join: unknown right column %s", rightOn[i])
+		}
+		if l.DType() != r.DType() {
+			return nil, fmt.Errorf("join: key dtype mismatch %s (%s) vs %s (%s)", l.Name(), l.DType(), r.Name(), r.DType())
+		}
+		leftKeys[i], rightKeys[i] = l, r
+	}
+	leftEnc, err := newKeyEncoder(leftKeys)
+	if err != nil {
+		return nil, fmt.Errorf("join: %w", err)
+	}
+	rightEnc, err := newKeyEncoder(rightKeys)
+	if err != nil {
+		return nil, fmt.Errorf("join: %w", err)
+	}
+
+	var li, ri []int
+	switch opts.How {
+	case JoinRight:
+		table := buildJoinTable(leftEnc, df.nrows, opts.JoinNulls)
+		ri, li = table.probe(rightEnc, other.nrows, joinProbeOuter)
+	default:
+		table := buildJoinTable(rightEnc, other.nrows, opts.JoinNulls)
+		mode := joinProbeInner
+		switch opts.How {
+		case JoinLeft, JoinFull:
+			mode = joinProbeOuter
+		case JoinSemi:
+			mode = joinProbeSemi
+		case JoinAnti:
+			mode = joinProbeAnti
+		}
+		li, ri = table.probe(leftEnc, df.nrows, mode)
+		if opts.How == JoinFull {
+			li, ri = appendUnmatched(li, ri, other.nrows)
+		}
+	}
+
+	if opts.How == JoinSemi || opts.How == JoinAnti {
+		cols := make([]array.Column, len(df.columns))
+		for i := range df.columns {
+			cols[i] = df.columns[i].Take(li)
+		}
+		return NewDataFrame(cols...)
+	}
+
+	coalesce := opts.How == JoinRight || opts.How == JoinFull
+	leftKeyPos := make(map[string]int, len(leftOn))
+	for i, name := range leftOn {
+		leftKeyPos[name] = i
+	}
+	out := make([]array.Column, 0, len(df.columns)+len(other.columns))
+	names := make(map[string]struct{}, cap(out))
+	for _, c := range df.columns {
+		var col array.Column
+		if k, isKey := leftKeyPos[c.Name()]; isKey && coalesce {
+			col, err = coalesceTake(c, rightKeys[k], li, ri)
+			if err != nil {
+				return nil, fmt.Errorf("join: %w", err)
+			}
+		} else {
+			col = c.Take(li)
+		}
+		out = append(out, col)
+		names[c.Name()] = struct{}{}
+	}
+	out, err = appendRightColumns(out, names, other, rightOn, ri, suffix)
+	if err != nil {
+		return nil, err
+	}
+	return NewDataFrame(out...)
+}
+
+// appendRightColumns gathers the right frame's columns, except skip, at rows
+// ri and appends them to out. Names clashing with names get suffix appended.
+func appendRightColumns(out []array.Column, names map[string]struct{}, right *DataFrame, skip []string, ri []int, suffix string) ([]array.Column, error) {
+	skipSet := make(map[string]struct{}, len(skip))
+	for _, name := range skip {
+		skipSet[name] = struct{}{}
+	}
+	for _, c := range right.columns {
+		if _, ok := skipSet[c.Name()]; ok {
+			continue
+		}
+		col := c.Take(ri)
+		if _, clash := names[c.Name()]; clash {
+			name := c.Name() + suffix
+			if _, clash := names[name]; clash {
+				return nil, fmt.Errorf("join: duplicate column %s", name)
+			}
+			var err error
+			if col, err = array.WithName(col, name); err != nil {
+				return nil, err
+			}
+		}
+		out = append(out, col)
+		names[col.Name()] = struct{}{}
+	}
+	return out, nil
+}
+
+// coalesceTake gathers left[li[i]], falling back to right[ri[i]] when the
+// row has no left match.
+func coalesceTake(left, right array.Column, li, ri []int) (array.Column, error) {
+	both, err := array.Concat(left, right)
+	if err != nil {
+		return nil, err
+	}
+	idx := make([]int, len(li))
+	for i := range li {
+		switch {
+		case li[i] >= 0:
+			idx[i] = li[i]
+		case ri[i] >= 0:
+			idx[i] = left.Len() + ri[i]
+		default:
+			idx[i] = -1
+		}
+	}
+	return both.Take(idx), nil
+}
+
+// appendUnmatched adds a row for every build-side row that no probe row
+// matched, in build order.
+func appendUnmatched(probeIdx, buildIdx []int, buildRows int) ([]int, []int) {
+	matched := make([]bool, buildRows)
+	for _, r := range buildIdx {
+		if r >= 0 {
+			matched[r] = true
+		}
+	}
+	for r := range matched {
+		if !matched[r] {
+			probeIdx = append(probeIdx, -1)
+			buildIdx = append(buildIdx, r)
+		}
+	}
+	return probeIdx, buildIdx
+}
+
+type joinProbeMode uint8
+
+const (
+	joinProbeInner joinProbeMode = iota
+	joinProbeOuter
+	joinProbeSemi
+	joinProbeAnti
+)
+
+// joinTable is a hash table over the build side. Rows are split into
+// partitions by key hash so large inputs can be built in parallel, one
+// partition per worker. Each partition maps a key to the first matching row;
+// next chains further matches in ascending row order.
+type joinTable struct {
+	enc       *keyEncoder
+	joinNulls bool
+	seed      maphash.Seed
+	parts     []map[string]int
+	next      []int
+}
+
+func buildJoinTable(enc *keyEncoder, nrows int, joinNulls bool) *joinTable {
+	workers := 1
+	if nrows >= parallelJoinThreshold {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	t := &joinTable{
+		enc:       enc,
+		joinNulls: joinNulls,
+		seed:      maphash.MakeSeed(),
+		parts:     make([]map[string]int, workers),
+		next:      make([]int, nrows),
+	}
+	if workers == 1 {
+		t.parts[0] = t.buildPartition(nil, 0, nrows)
+		return t
+	}
+
+	// Hash every row once, then let each worker insert the rows of its own
+	// partition; workers write disjoint entries of next.
+	hashes := make([]uint64, nrows)
+	parallelRanges(nrows, workers, func(start, end int) {
+		var buf []byte
+		for row := start; row < end; row++ {
+			buf = enc.appendRow(buf[:0], row)
+			hashes[row] = maphash.Bytes(t.seed, buf)
+		}
+	})
+	var wg sync.WaitGroup
+	for p := range t.parts {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			t.parts[p] = t.buildPartition(hashes, p, nrows)
+		}(p)
+	}
+	wg.Wait()
+	return t
+}
+
+func (t *joinTable) buildPartition(hashes []uint64, p, nrows int) map[string]int {
+	m := make(map[string]int, nrows/len(t.parts)+1)
+	var buf []byte
+	// Insert in descending order so each chain lists rows ascending.
+	for row := nrows - 1; row >= 0; row-- {
+		if hashes != nil && int(hashes[row]%uint64(len(t.parts))) != p {
+			continue
+		}
+		if !t.joinNulls && t.enc.hasNull(row) {
+			continue
+		}
+		buf = t.enc.appendRow(buf[:0], row)
+		head, ok := m[string(buf)]
+		if !ok {
+			head = -1
+		}
+		t.next[row] = head
+		m[string(buf)] = row
+	}
+	return m
+}
+
+func (t *joinTable) lookup(key []byte) int {
+	p := 0
+	if len(t.parts) > 1 {
+		p = int(maphash.Bytes(t.seed, key) % uint64(len(t.parts)))
+	}
+	if head, ok := t.parts[p][string(key)]; ok {
+		return head
+	}
+	return -1
+}
+
+// probe matches every probe row against the table and returns aligned
+// (probe, build) row pairs. A build index of -1 marks an unmatched outer row.
+// Semi and anti joins return only probe rows.
+func (t *joinTable) probe(enc *keyEncoder, nrows int, mode joinProbeMode) ([]int, []int) {
+	workers := 1
+	if nrows >= parallelJoinThreshold {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	probeParts := make([][]int, workers)
+	buildParts := make([][]int, workers)
+	chunk := max((nrows+workers-1)/workers, 1)
+	parallelRanges(nrows, workers, func(start, end int) {
+		var probeIdx, buildIdx []int
+		var buf []byte
+		for row := start; row < end; row++ {
+			head := -1
+			if t.joinNulls || !enc.hasNull(row) {
+				buf = enc.appendRow(buf[:0], row)
+				head = t.lookup(buf)
+			}
+			switch mode {
+			case joinProbeSemi:
+				if head >= 0 {
+					probeIdx = append(probeIdx, row)
+				}
+				continue
+			case joinProbeAnti:
+				if head < 0 {
+					probeIdx = append(probeIdx, row)
+				}
+				continue
+			}
+			if head < 0 {
+				if mode == joinProbeOuter {
+					probeIdx = append(probeIdx, row)
+					buildIdx = append(buildIdx, -1)
+				}
+				continue
+			}
+			for r := head; r >= 0; r = t.next[r] {
+				probeIdx = append(probeIdx, row)
+				buildIdx = append(buildIdx, r)
+			}
+		}
+		w := start / chunk
+		probeParts[w], buildParts[w] = probeIdx, buildIdx
+	})
+
+	n := 0
+	for _, p := range probeParts {
+		n += len(p)
+	}
+	probeIdx := make([]int, 0, n)
+	buildIdx := make([]int, 0, n)
+	for w := range probeParts {
+		probeIdx = append(probeIdx, probeParts[w]...)
+		buildIdx = append(buildIdx, buildParts[w]...)
+	}
+	return probeIdx, buildIdx
+}
+
+// parallelRanges splits [0, n) into at most workers contiguous ranges of
+// equal size and runs fn on each concurrently.
+func parallelRanges(n, workers int, fn func(start, end int)) {
+	if workers <= 1 || n == 0 {
+		fn(0, n)
+		return
+	}
+	chunk := (n + workers - 1) / workers
+	var wg sync.WaitGroup
+	for start := 0; start < n; start += chunk {
+		end := min(start+chunk, n)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fn(start, end)
+		}()
+	}
+	wg.Wait()
+}
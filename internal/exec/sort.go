@@ -0,0 +1,88 @@
+package exec
+
+import (
+	"fmt"
+
+	"grizzly/internal/array"
+)
+
+// SortKey is one column of a multi-column sort. By default values ascend
+// and NULLs come last.
+type SortKey struct {
+	Col        string
+	Desc       bool
+	NullsFirst bool
+}
+
+type SortOptions struct {
+	// MaintainOrder keeps rows with equal keys in input order. Without it
+	// the order of ties is unspecified, which lets the sort skip the
+	// row-index tie-break.
+	MaintainOrder bool
+}
+
+// SortKeys orders rows by keys, compared in order: a later key only decides
+// between rows equal on every earlier one.
+func (df *DataFrame) SortKeys(keys []SortKey, opts SortOptions) (*DataFrame, error) {
+	cmp, err := df.keysComparator(keys)
+	if err != nil {
+		return nil, err
+	}
+	order := make([]int, df.nrows)
+	for i := range order {
+		order[i] = i
+	}
+	parallelSort(order, cmp, opts.MaintainOrder, df.nrows >= parallelSortThreshold)
+	cols := make([]array.Column, len(df.columns))
+	for i := range df.columns {
+		cols[i] = df.columns[i].Take(order)
+	}
+	return NewDataFrame(cols...)
+}
+
+// keysComparator chains the comparators of keys into one.
+func (df *DataFrame) keysComparator(keys []SortKey) (func(a, b int) int, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("sort requires at least one column")
+	}
+	cmps := make([]func(a, b int) int, len(keys))
+	for i, k := range keys {
+		c, ok := df.Column(k.Col)
+		if !ok {
+			return nil, fmt.Errorf("unknown column %s", k.Col)
+		}
+		cmp, err := sortComparator(c, k.Desc)
+		if err != nil {
+			return nil, err
+		}
+		if k.NullsFirst {
+			cmp = nullsFirst(c, cmp)
+		}
+		cmps[i] = cmp
+	}
+	if len(cmps) == 1 {
+		return cmps[0], nil
+	}
+	return func(a, b int) int {
+		for _, c := range cmps {
+			if o := c(a, b); o != 0 {
+				return o
+			}
+		}
+		return 0
+	}, nil
+}
+
+// nullsFirst moves the NULLs of c ahead of every value under cmp, which
+// must already order NULLs last.
+func nullsFirst(c array.Column, cmp func(a, b int) int) func(a, b int) int {
+	return func(a, b int) int {
+		if an, bn := c.IsNull(a), c.IsNull(b); an != bn {
+			if an {
+				return -1
+			}
+			return 1
+		}
+		return cmp(a, b)
+	}
+}
@@ -0,0 +1,112 @@
+package exec
+
+import (
+	"container/heap"
+	"fmt"
+	"runtime"
+	"sort"
+
+	"grizzly/internal/array"
+)
+
+// parallelTopKThreshold is the row count above which TopK splits the scan
+// across workers.
+const parallelTopKThreshold = 100000
+
+// TopK returns the first k rows of a stable sort by the columns in by,
+// compared in order, without sorting the whole frame: each worker keeps a
+// bounded heap over its share of the rows and the survivors are merged.
+// With desc the largest values come first. NULLs rank after every value in
+// both directions, and ties keep input order, as in SortBy.
+func (df *DataFrame) TopK(k int, by []string, desc bool) (*DataFrame, error) {
+	keys := make([]SortKey, len(by))
+	for i, name := range by {
+		keys[i] = SortKey{Col: name, Desc: desc}
+	}
+	return df.TopKBy(k, keys)
+}
+
+// TopKBy is TopK with a direction and NULL placement per key, giving the
+// first k rows of a stable SortKeys by keys.
+func (df *DataFrame) TopKBy(k int, keys []SortKey) (*DataFrame, error) {
+	if k < 0 {
+		return nil, fmt.Errorf("top_k k must be >= 0")
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("top_k requires at least one column")
+	}
+	cmp, err := df.keysComparator(keys)
+	if err != nil {
+		return nil, err
+	}
+	order := topKRows(df.nrows, k, cmp)
+	cols := make([]array.Column, len(df.columns))
+	for i := range df.columns {
+		cols[i] = df.columns[i].Take(order)
+	}
+	return NewDataFrame(cols...)
+}
+
+// BottomK is TopK from the other end: the k rows a sort in the opposite
+// direction would put first. NULLs still rank last.
+func (df *DataFrame) BottomK(k int, by []string, desc bool) (*DataFrame, error) {
+	return df.TopK(k, by, !desc)
+}
+
+// topKRows returns, in order, the k rows that a stable sort by cmp would put
+// first.
+func topKRows(n, k int, cmp func(a, b int) int) []int {
+	k = min(k, n)
+	if k == 0 {
+		return []int{}
+	}
+	less := func(a, b int) bool {
+		if o := cmp(a, b); o != 0 {
+			return o < 0
+		}
+		return a < b
+	}
+	workers := 1
+	if n >= parallelTopKThreshold {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	chunk := (n + workers - 1) / workers
+	heaps := make([]*rowHeap, (n+chunk-1)/chunk)
+	parallelRanges(n, workers, func(start, end int) {
+		h := &rowHeap{rows: make([]int, 0, min(k, end-start)), less: less}
+		for row := start; row < end; row++ {
+			if len(h.rows) < k {
+				heap.Push(h, row)
+			} else if less(row, h.rows[0]) {
+				h.rows[0] = row
+				heap.Fix(h, 0)
+			}
+		}
+		heaps[start/chunk] = h
+	})
+
+	var out []int
+	for _, h := range heaps {
+		out = append(out, h.rows...)
+	}
+	sort.Slice(out, func(i, j int) bool { return less(out[i], out[j]) })
+	return out[:k]
+}
+
+// rowHeap is a max-heap of row indices under less, so its root is the worst
+// row kept so far.
+type rowHeap struct {
+	rows []int
+	less func(a, b int) bool
+}
+
+func (h *rowHeap) Len() int           { return len(h.rows) }
+func (h *rowHeap) Less(i, j int) bool { return h.less(h.rows[j], h.rows[i]) }
+func (h *rowHeap) Swap(i, j int)      { h.rows[i], h.rows[j] = h.rows[j], h.rows[i] }
+func (h *rowHeap) Push(x any)         { h.rows = append(h.rows, x.(int)) }
+func (h *rowHeap) Pop() any {
+	n := len(h.rows)
+	v := h.rows[n-1]
+	h.rows = h.rows[:n-1]
+	return v
+}
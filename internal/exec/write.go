@@ -0,0 +1,109 @@
+package exec
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+
+	"grizzly/internal/io/chunked"
+)
+
+// jsonWriteChunkRows is the number of rows encoded as one unit of output by
+// WriteJSON and WriteNDJSON.
+const jsonWriteChunkRows = 8192
+
+// JSONWriteOptions configure WriteJSON and WriteNDJSON.
+type JSONWriteOptions struct {
+	// Parallel encodes chunks of rows on up to GOMAXPROCS workers. Output is
+	// the same either way.
+	Parallel bool
+}
+
+// WriteJSON streams df to w as a JSON array of row objects, the same bytes
+// MarshalRowsJSON returns.
+func (df *DataFrame) WriteJSON(w io.Writer, opts JSONWriteOptions) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+	enc := newRowEncoder(df)
+	err := chunked.Write(w, df.nrows, jsonWriteChunkRows, opts.Parallel, func(start, end int) []byte {
+		var buf bytes.Buffer
+		for i := start; i < end; i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			enc.encodeRow(&buf, i)
+		}
+		return buf.Bytes()
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "]")
+	return err
+}
+
+// WriteNDJSON streams df to w as newline-delimited JSON, one row object per
+// line.
+func (df *DataFrame) WriteNDJSON(w io.Writer, opts JSONWriteOptions) error {
+	enc := newRowEncoder(df)
+	return chunked.Write(w, df.nrows, jsonWriteChunkRows, opts.Parallel, func(start, end int) []byte {
+		var buf bytes.Buffer
+		for i := start; i < end; i++ {
+			enc.encodeRow(&buf, i)
+			buf.WriteByte('\n')
+		}
+		return buf.Bytes()
+	})
+}
+
+// rowEncoder writes rows of a frame as JSON objects. It is read-only once
+// built, so workers can share it.
+type rowEncoder struct {
+	views      []columnView
+	quotedKeys []string
+}
+
+func newRowEncoder(df *DataFrame) rowEncoder {
+	quotedKeys := make([]string, len(df.columns))
+	for i := range df.columns {
+		quotedKeys[i] = strconv.Quote(df.columns[i].Name())
+	}
+	return rowEncoder{views: buildColumnViews(df.columns), quotedKeys: quotedKeys}
+}
+
+func (e rowEncoder) encodeRow(buf *bytes.Buffer, i int) {
+	var numScratch [64]byte
+	buf.WriteByte('{')
+	for j, v := range e.views {
+		if j > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(e.quotedKeys[j])
+		buf.WriteByte(':')
+		if v.isNull(i) {
+			buf.WriteString("null")
+			continue
+		}
+		switch v.kind {
+		case colKindInt64:
+			buf.Write(strconv.AppendInt(numScratch[:0], v.i64.Value(i), 10))
+		case colKindFloat64:
+			buf.Write(strconv.AppendFloat(numScratch[:0], v.f64.Value(i), 'g', -1, 64))
+		case colKindBool:
+			if v.b.Value(i) {
+				buf.WriteString("true")
+			} else {
+				buf.WriteString("false")
+			}
+		case colKindUtf8:
+			s, e := v.s.ByteRange(i)
+			writeJSONStringEscapedBytes(buf, v.s.Bytes()[s:e])
+		case colKindTemporal:
+			writeJSONTemporal(buf, v.t, i)
+		case colKindNumeric:
+			buf.WriteString(v.t.ValueString(i))
+		}
+	}
+	buf.WriteByte('}')
+}
@@ -0,0 +1,156 @@
+package expr
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+
+	"grizzly/internal/array"
+)
+
+// Eq, Neq, Lt, Lte, Gt and Gte compare two value expressions row by row.
+// Integer operands compare exactly, uint64 included; when either side is a
+// float both compare as float64. Other dtypes must match exactly. A NULL on either side gives an invalid
+// mask entry, as for literal comparisons.
+func Eq(a, b Value) Expr  { return valueCompareExpr{left: a, op: cmpEq, right: b} }
+func Neq(a, b Value) Expr { return valueCompareExpr{left: a, op: cmpNeq, right: b} }
+func Lt(a, b Value) Expr  { return valueCompareExpr{left: a, op: cmpLt, right: b} }
+func Lte(a, b Value) Expr { return valueCompareExpr{left: a, op: cmpLte, right: b} }
+func Gt(a, b Value) Expr  { return valueCompareExpr{left: a, op: cmpGt, right: b} }
+func Gte(a, b Value) Expr { return valueCompareExpr{left: a, op: cmpGte, right: b} }
+
+type valueCompareExpr struct {
+	left  Value
+	op    cmpOp
+	right Value
+}
+
+func (e valueCompareExpr) Eval(f Frame) (Mask, error) {
+	l, err := e.left.EvalValue(f)
+	if err != nil {
+		return Mask{}, err
+	}
+	r, err := e.right.EvalValue(f)
+	if err != nil {
+		return Mask{}, err
+	}
+	if l.Len() != r.Len() {
+		return Mask{}, fmt.Errorf("comparison length mismatch %d vs %d", l.Len(), r.Len())
+	}
+	row, err := rowComparator(l, r, e.op)
+	if err != nil {
+		return Mask{}, err
+	}
+	vals := make([]bool, l.Len())
+	valid := make([]bool, l.Len())
+	for i := range vals {
+		if l.IsNull(i) || r.IsNull(i) {
+			continue
+		}
+		valid[i] = true
+		vals[i] = row(i)
+	}
+	return Mask{Data: vals, Valid: valid}, nil
+}
+
+// rowComparator returns op applied to row i of l and r. Integer operands of
+// any width and signedness compare exactly; when either side is a float both
+// compare as float64.
+func rowComparator(l, r array.Column, op cmpOp) (func(i int) bool, error) {
+	if ord, ok := intOrder(l, r); ok {
+		return func(i int) bool { return cmpInt(op, ord(i)) }, nil
+	}
+	if lf, rf, ok := floatPair(l, r); ok {
+		return func(i int) bool { return cmpFloat64(op, lf(i), rf(i)) }, nil
+	}
+	if l.DType() != r.DType() {
+		return nil, fmt.Errorf("cannot compare %s with %s", l.DType(), r.DType())
+	}
+	switch lc := l.(type) {
+	case *array.Utf8Column:
+		rc := r.(*array.Utf8Column)
+		lb, rb := lc.Bytes(), rc.Bytes()
+		return func(i int) bool {
+			ls, le := lc.ByteRange(i)
+			rs, re := rc.ByteRange(i)
+			return cmpInt(op, bytes.Compare(lb[ls:le], rb[rs:re]))
+		}, nil
+	case *array.BoolColumn:
+		rc := r.(*array.BoolColumn)
+		return func(i int) bool { return cmpBool(op, lc.Value(i), rc.Value(i)) }, nil
+	}
+	lv, ok := int64Accessor(l)
+	if !ok {
+		return nil, fmt.Errorf("unsupported compare column type %s", l.DType())
+	}
+	rv, _ := int64Accessor(r)
+	return func(i int) bool { return cmpInt64(op, lv(i), rv(i)) }, nil
+}
+
+// intOrder orders row i of two integer columns. uint64 values are compared
+// against signed ones by sign first, so no value is rounded.
+func intOrder(l, r array.Column) (func(i int) int, bool) {
+	lu, lUint := l.(*array.UInt64Column)
+	ru, rUint := r.(*array.UInt64Column)
+	li, lInt := array.Int64Accessor(l)
+	ri, rInt := array.Int64Accessor(r)
+	switch {
+	case lUint && rUint:
+		return func(i int) int { return cmp.Compare(lu.Value(i), ru.Value(i)) }, true
+	case lUint && rInt:
+		return func(i int) int { return compareUintInt(lu.Value(i), ri(i)) }, true
+	case lInt && rUint:
+		return func(i int) int { return -compareUintInt(ru.Value(i), li(i)) }, true
+	case lInt && rInt:
+		return func(i int) int { return cmp.Compare(li(i), ri(i)) }, true
+	default:
+		return nil, false
+	}
+}
+
+// compareUintInt orders an unsigned value against a signed one exactly.
+func compareUintInt(a uint64, b int64) int {
+	if b < 0 {
+		return 1
+	}
+	return cmp.Compare(a, uint64(b))
+}
+
+// floatPair returns float64 accessors when both columns are numeric and at
+// least one is a float.
+func floatPair(l, r array.Column) (func(int) float64, func(int) float64, bool) {
+	lf, lIsFloat, lok := numericAccessor(l)
+	rf, rIsFloat, rok := numericAccessor(r)
+	if !lok || !rok || (!lIsFloat && !rIsFloat) {
+		return nil, nil, false
+	}
+	return lf, rf, true
+}
+
+func numericAccessor(col array.Column) (func(int) float64, bool, bool) {
+	get, ok := array.Float64Accessor(col)
+	if !ok {
+		return nil, false, false
+	}
+	switch col.(type) {
+	case *array.Float64Column, *array.Float32Column:
+		return get, true, true
+	}
+	return get, false, true
+}
+
+func int64Accessor(col array.Column) (func(int) int64, bool) {
+	if get, ok := array.Int64Accessor(col); ok {
+		return get, true
+	}
+	switch c := col.(type) {
+	case *array.DateColumn:
+		return func(i int) int64 { return int64(c.Value(i)) }, true
+	case *array.DatetimeColumn:
+		return c.Value, true
+	case *array.DurationColumn:
+		return c.Value, true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,236 @@
+package expr
+
+import (
+	"fmt"
+
+	"grizzly/internal/array"
+)
+
+// When picks, for every row, the value of the first branch whose condition
+// holds; rows where no condition holds take otherwise, or NULL when
+// otherwise is nil. A NULL condition counts as false. Branch values must
+// share a dtype, except that numeric branches of different types are
+// promoted to int64 when all are integers and to float64 otherwise.
+func When(conds []Expr, thens []Value, otherwise Value) Value {
+	return whenExpr{
+		conds:     append([]Expr(nil), conds...),
+		thens:     append([]Value(nil), thens...),
+		otherwise: otherwise,
+	}
+}
+
+type whenExpr struct {
+	conds     []Expr
+	thens     []Value
+	otherwise Value
+}
+
+func (e whenExpr) EvalValue(f Frame) (array.Column, error) {
+	if len(e.conds) == 0 || len(e.conds) != len(e.thens) {
+		return nil, fmt.Errorf("when: every condition needs a then value")
+	}
+	n := f.Height()
+	pick := make([]int, n)
+	for i := range pick {
+		pick[i] = -1
+	}
+	for b, cond := range e.conds {
+		m, err := cond.Eval(f)
+		if err != nil {
+			return nil, err
+		}
+		for i := range pick {
+			if pick[i] < 0 && i < len(m.Valid) && m.Valid[i] && m.Data[i] {
+				pick[i] = b
+			}
+		}
+	}
+	branches := e.thens
+	if e.otherwise != nil {
+		branches = append(branches[:len(branches):len(branches)], e.otherwise)
+		for i := range pick {
+			if pick[i] < 0 {
+				pick[i] = len(e.thens)
+			}
+		}
+	}
+	cols, err := evalBranches(f, branches, "when")
+	if err != nil {
+		return nil, err
+	}
+	return pickRows(OutputName(e), cols, pick)
+}
+
+// Coalesce takes, for every row, the first non-NULL value among vals. Dtype
+// rules are those of When.
+func Coalesce(vals ...Value) Value { return coalesceExpr{vals: append([]Value(nil), vals...)} }
+
+type coalesceExpr struct {
+	vals []Value
+}
+
+func (e coalesceExpr) EvalValue(f Frame) (array.Column, error) {
+	if len(e.vals) == 0 {
+		return nil, fmt.Errorf("coalesce: no inputs")
+	}
+	cols, err := evalBranches(f, e.vals, "coalesce")
+	if err != nil {
+		return nil, err
+	}
+	pick := make([]int, f.Height())
+	for i := range pick {
+		pick[i] = -1
+		for b, c := range cols {
+			if !c.IsNull(i) {
+				pick[i] = b
+				break
+			}
+		}
+	}
+	return pickRows(OutputName(e), cols, pick)
+}
+
+// evalBranches evaluates vals and brings them to one dtype.
+func evalBranches(f Frame, vals []Value, fn string) ([]array.Column, error) {
+	cols := make([]array.Column, len(vals))
+	promote := false
+	to := array.Int(64)
+	for i, v := range vals {
+		col, err := v.EvalValue(f)
+		if err != nil {
+			return nil, err
+		}
+		cols[i] = col
+		if !array.IsInteger(col.DType()) {
+			to = array.Float(64)
+		}
+		if col.DType() == cols[0].DType() {
+			continue
+		}
+		if !isNumeric(col) || !isNumeric(cols[0]) {
+			return nil, fmt.Errorf("%s: branch dtype mismatch %s vs %s", fn, cols[0].DType(), col.DType())
+		}
+		promote = true
+	}
+	if promote {
+		for i, col := range cols {
+			var err error
+			if cols[i], err = array.Cast(col, to); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return cols, nil
+}
+
+func isNumeric(col array.Column) bool { return array.IsNumeric(col.DType()) }
+
+// pickRows builds a column whose row i is row i of cols[pick[i]], or NULL
+// when pick[i] is -1.
+func pickRows(name string, cols []array.Column, pick []int) (array.Column, error) {
+	all, err := array.Concat(cols...)
+	if err != nil {
+		return nil, err
+	}
+	n := len(pick)
+	order := make([]int, n)
+	for i, b := range pick {
+		order[i] = -1
+		if b >= 0 {
+			order[i] = b*n + i
+		}
+	}
+	return array.WithName(all.Take(order), name)
+}
+
+type FillStrategy uint8
+
+const (
+	// FillForward carries the last non-NULL value forward.
+	FillForward FillStrategy = iota + 1
+	// FillBackward takes the next non-NULL value.
+	FillBackward
+	// FillMean uses the mean of the non-NULL values; the result is float64.
+	FillMean
+)
+
+// FillNull replaces NULLs in v with the value of fill; see Coalesce.
+func FillNull(v, fill Value) Value { return fillNullExpr{child: v, fill: fill} }
+
+// FillNullStrategy replaces NULLs in v using values of v itself. Leading
+// (forward) or trailing (backward) NULLs with nothing to copy stay NULL.
+func FillNullStrategy(v Value, strategy FillStrategy) Value {
+	return fillNullExpr{child: v, strategy: strategy}
+}
+
+type fillNullExpr struct {
+	child    Value
+	fill     Value
+	strategy FillStrategy
+}
+
+func (e fillNullExpr) EvalValue(f Frame) (array.Column, error) {
+	if e.fill != nil {
+		return Coalesce(e.child, e.fill).EvalValue(f)
+	}
+	col, err := e.child.EvalValue(f)
+	if err != nil {
+		return nil, err
+	}
+	n := col.Len()
+	order := make([]int, n)
+	switch e.strategy {
+	case FillForward:
+		last := -1
+		for i := range order {
+			if !col.IsNull(i) {
+				last = i
+			}
+			order[i] = last
+		}
+	case FillBackward:
+		next := -1
+		for i := n - 1; i >= 0; i-- {
+			if !col.IsNull(i) {
+				next = i
+			}
+			order[i] = next
+		}
+	case FillMean:
+		return fillMean(col)
+	default:
+		return nil, fmt.Errorf("fill_null: invalid strategy")
+	}
+	return col.Take(order), nil
+}
+
+func fillMean(col array.Column) (array.Column, error) {
+	if !isNumeric(col) {
+		return nil, fmt.Errorf("fill_null: mean requires a numeric column, got %s", col.DType())
+	}
+	fc, err := array.Cast(col, array.Float(64))
+	if err != nil {
+		return nil, err
+	}
+	c := fc.(*array.Float64Column)
+	var sum float64
+	var count int
+	for i := 0; i < c.Len(); i++ {
+		if !c.IsNull(i) {
+			sum += c.Value(i)
+			count++
+		}
+	}
+	if count == 0 {
+		return c, nil
+	}
+	mean := sum / float64(count)
+	out := make([]float64, c.Len())
+	for i := range out {
+		out[i] = mean
+		if !c.IsNull(i) {
+			out[i] = c.Value(i)
+		}
+	}
+	return array.NewFloat64ColumnOwned(c.Name(), out, array.NewBitmap(len(out), true)), nil
+}
@@ -0,0 +1,183 @@
+package expr
+
+// Conjuncts splits e on And into the predicates that must all hold.
+func Conjuncts(e Expr) []Expr {
+	if x, ok := e.(logicalExpr); ok && x.op == "and" {
+		return append(Conjuncts(x.left), Conjuncts(x.right)...)
+	}
+	return []Expr{e}
+}
+
+// AndAll combines predicates left to right with And; it is the inverse of
+// Conjuncts.
+func AndAll(es []Expr) Expr {
+	out := es[0]
+	for _, e := range es[1:] {
+		out = And(out, e)
+	}
+	return out
+}
+
+// Cost estimates the relative per-row work of evaluating e. The numbers only
+// need to rank predicates against each other: a null check is cheaper than a
+// numeric comparison, which is cheaper than a substring search or a regex.
+func Cost(e Expr) float64 {
+	switch x := e.(type) {
+	case isNullExpr:
+		return 0.5
+	case evenExpr:
+		return 1
+	case compareExpr:
+		if _, ok := x.right.(string); ok {
+			return 2
+		}
+		return 1
+	case inExpr:
+		return 1.5
+	case valueCompareExpr:
+		return 1 + valueCost(x.left) + valueCost(x.right)
+	case strPredicate:
+		if x.op == strMatches {
+			return 10
+		}
+		return 3
+	case notExpr:
+		return Cost(x.child) + 0.1
+	case logicalExpr:
+		return Cost(x.left) + Cost(x.right)
+	default:
+		return 5
+	}
+}
+
+func valueCost(v Value) float64 {
+	switch x := v.(type) {
+	case ColRef, litExpr:
+		return 0.5
+	case arithExpr:
+		return 1 + valueCost(x.left) + valueCost(x.right)
+	case negExpr:
+		return 1 + valueCost(x.child)
+	case castExpr:
+		return 2 + valueCost(x.child)
+	case aliasExpr:
+		return valueCost(x.child)
+	default:
+		return 5
+	}
+}
+
+// Selectivity guesses the fraction of rows e keeps, without statistics:
+// equality keeps few rows, ranges about a third, inequality most.
+func Selectivity(e Expr) float64 {
+	switch x := e.(type) {
+	case isNullExpr:
+		if x.negate {
+			return 0.9
+		}
+		return 0.1
+	case evenExpr:
+		return 0.5
+	case compareExpr:
+		return opSelectivity(x.op)
+	case valueCompareExpr:
+		return opSelectivity(x.op)
+	case inExpr:
+		return min(0.1*float64(len(x.vals)), 0.9)
+	case strPredicate:
+		if x.op == strStartsWith || x.op == strEndsWith {
+			return 0.2
+		}
+		return 0.25
+	case notExpr:
+		return 1 - Selectivity(x.child)
+	case logicalExpr:
+		a, b := Selectivity(x.left), Selectivity(x.right)
+		if x.op == "and" {
+			return a * b
+		}
+		return a + b - a*b
+	default:
+		return 0.5
+	}
+}
+
+func opSelectivity(op cmpOp) float64 {
+	switch op {
+	case cmpEq:
+		return 0.1
+	case cmpNeq:
+		return 0.9
+	default:
+		return 0.33
+	}
+}
+
+// IsRowLocal reports whether e decides every row from that row's values
+// alone. Only such predicates may be split, reordered, evaluated on a subset
+// of rows or moved past a sort or join; a strategy fill reads its neighbours
+// or the whole column, and a strict cast reports row numbers, so both must
+// see the frame they were written against.
+func IsRowLocal(e Expr) bool {
+	switch x := e.(type) {
+	case compareExpr, inExpr, evenExpr, isNullExpr:
+		return true
+	case valueCompareExpr:
+		return IsRowLocalValue(x.left) && IsRowLocalValue(x.right)
+	case strPredicate:
+		return IsRowLocalValue(x.child)
+	case notExpr:
+		return IsRowLocal(x.child)
+	case logicalExpr:
+		return IsRowLocal(x.left) && IsRowLocal(x.right)
+	default:
+		return false
+	}
+}
+
+// IsRowLocalValue is IsRowLocal for value expressions.
+func IsRowLocalValue(v Value) bool {
+	switch x := v.(type) {
+	case ColRef, litExpr:
+		return true
+	case arithExpr:
+		return IsRowLocalValue(x.left) && IsRowLocalValue(x.right)
+	case negExpr:
+		return IsRowLocalValue(x.child)
+	case castExpr:
+		// A strict cast fails on the first bad row and names it, so it must
+		// see the rows it was written against.
+		return !x.strict && IsRowLocalValue(x.child)
+	case aliasExpr:
+		return IsRowLocalValue(x.child)
+	case strLenExpr:
+		return IsRowLocalValue(x.child)
+	case strMapExpr:
+		return IsRowLocalValue(x.child)
+	case concatStrExpr:
+		return allRowLocal(x.vals)
+	case whenExpr:
+		for _, c := range x.conds {
+			if !IsRowLocal(c) {
+				return false
+			}
+		}
+		return allRowLocal(x.thens) && (x.otherwise == nil || IsRowLocalValue(x.otherwise))
+	case coalesceExpr:
+		return allRowLocal(x.vals)
+	case fillNullExpr:
+		// FillNull with a value is a coalesce; strategies read other rows.
+		return x.fill != nil && IsRowLocalValue(x.child) && IsRowLocalValue(x.fill)
+	default:
+		return false
+	}
+}
+
+func allRowLocal(vs []Value) bool {
+	for _, v := range vs {
+		if !IsRowLocalValue(v) {
+			return false
+		}
+	}
+	return true
+}
@@ -1,8 +1,11 @@
 package expr
 
 import (
+	"cmp"
 	"fmt"
+	"math"
 	"strconv"
+	"time"
 
 	"grizzly/internal/array"
 )
@@ -32,12 +35,14 @@ type ColRef struct {
 
 func Col(name string) ColRef { return ColRef{Name: name} }
 
-func (c ColRef) Eq(v any) Expr   { return compareExpr{left: c.Name, op: cmpEq, right: v} }
-func (c ColRef) Neq(v any) Expr  { return compareExpr{left: c.Name, op: cmpNeq, right: v} }
-func (c ColRef) Lt(v any) Expr   { return compareExpr{left: c.Name, op: cmpLt, right: v} }
-func (c ColRef) Lte(v any) Expr  { return compareExpr{left: c.Name, op: cmpLte, right: v} }
-func (c ColRef) Gt(v any) Expr   { return compareExpr{left: c.Name, op: cmpGt, right: v} }
-func (c ColRef) Gte(v any) Expr  { return compareExpr{left: c.Name, op: cmpGte, right: v} }
+// Eq, Neq, Lt, Lte, Gt and Gte compare the column with v, which is either a
+// Go literal or a Value such as another ColRef.
+func (c ColRef) Eq(v any) Expr   { return c.compare(cmpEq, v) }
+func (c ColRef) Neq(v any) Expr  { return c.compare(cmpNeq, v) }
+func (c ColRef) Lt(v any) Expr   { return c.compare(cmpLt, v) }
+func (c ColRef) Lte(v any) Expr  { return c.compare(cmpLte, v) }
+func (c ColRef) Gt(v any) Expr   { return c.compare(cmpGt, v) }
+func (c ColRef) Gte(v any) Expr  { return c.compare(cmpGte, v) }
 func (c ColRef) Even() Expr      { return evenExpr{col: c.Name} }
 func (c ColRef) IsNull() Expr    { return isNullExpr{col: c.Name, negate: false} }
 func (c ColRef) IsNotNull() Expr { return isNullExpr{col: c.Name, negate: true} }
@@ -45,6 +50,13 @@ func (c ColRef) In(vals ...any) Expr {
 	return inExpr{col: c.Name, vals: append([]any(nil), vals...)}
 }
 
+func (c ColRef) compare(op cmpOp, v any) Expr {
+	if rv, ok := v.(Value); ok {
+		return valueCompareExpr{left: c, op: op, right: rv}
+	}
+	return compareExpr{left: c.Name, op: op, right: v}
+}
+
 func Not(e Expr) Expr { return notExpr{child: e} }
 
 type compareExpr struct {
@@ -78,7 +90,9 @@ func (e inExpr) Eval(f Frame) (Mask, error) {
 	}
 
 	switch c := col.(type) {
-	case *array.Int64Column:
+	case *array.Int64Column, *array.Int32Column, *array.Int16Column, *array.Int8Column,
+		*array.UInt32Column, *array.UInt16Column, *array.UInt8Column:
+		get, _ := array.Int64Accessor(c)
 		set := make(map[int64]struct{}, len(e.vals))
 		for i := range e.vals {
 			v, ok := literalToInt64(e.vals[i])
@@ -87,6 +101,27 @@ func (e inExpr) Eval(f Frame) (Mask, error) {
 			}
 			set[v] = struct{}{}
 		}
+		for i := range vals {
+			if c.IsNull(i) {
+				continue
+			}
+			valid[i] = true
+			_, ok := set[get(i)]
+			vals[i] = ok
+		}
+	case *array.UInt64Column:
+		set := make(map[uint64]struct{}, len(e.vals))
+		for i := range e.vals {
+			v, ok := literalToUint64(e.vals[i])
+			if !ok {
+				if n, isInt := literalToInt64(e.vals[i]); isInt && n < 0 {
+					// A negative value can never match.
+					continue
+				}
+				return Mask{}, fmt.Errorf("cannot build uint64 set")
+			}
+			set[v] = struct{}{}
+		}
 		for i := range vals {
 			if c.IsNull(i) {
 				continue
@@ -175,6 +210,18 @@ func (e compareExpr) Eval(f Frame) (Mask, error) {
 			valid[i] = true
 			vals[i] = cmpFloat64(e.op, c.Value(i), r)
 		}
+	case *array.UInt64Column:
+		ord, ok := uint64LiteralOrder(e.right)
+		if !ok {
+			return Mask{}, fmt.Errorf("cannot compare uint64 column with literal")
+		}
+		for i := range vals {
+			if c.IsNull(i) {
+				continue
+			}
+			valid[i] = true
+			vals[i] = cmpInt(e.op, ord(c.Value(i)))
+		}
 	case *array.Utf8Column:
 		lit := []byte(fmt.Sprint(e.right))
 		for i := range vals {
@@ -197,8 +244,71 @@ func (e compareExpr) Eval(f Frame) (Mask, error) {
 			valid[i] = true
 			vals[i] = cmpBool(e.op, c.Value(i), r)
 		}
+	case *array.DateColumn:
+		r, ok := literalToDate(e.right)
+		if !ok {
+			return Mask{}, fmt.Errorf("cannot compare date column with literal")
+		}
+		for i := range vals {
+			if c.IsNull(i) {
+				continue
+			}
+			valid[i] = true
+			vals[i] = cmpInt64(e.op, int64(c.Value(i)), int64(r))
+		}
+	case *array.DatetimeColumn:
+		r, ok := literalToDatetime(e.right, c.Unit(), c.Location())
+		if !ok {
+			return Mask{}, fmt.Errorf("cannot compare datetime column with literal")
+		}
+		for i := range vals {
+			if c.IsNull(i) {
+				continue
+			}
+			valid[i] = true
+			vals[i] = cmpInt64(e.op, c.Value(i), r)
+		}
+	case *array.DurationColumn:
+		r, ok := literalToDuration(e.right, c.Unit())
+		if !ok {
+			return Mask{}, fmt.Errorf("cannot compare duration column with literal")
+		}
+		for i := range vals {
+			if c.IsNull(i) {
+				continue
+			}
+			valid[i] = true
+			vals[i] = cmpInt64(e.op, c.Value(i), r)
+		}
 	default:
-		return Mask{}, fmt.Errorf("unsupported compare column type")
+		// Narrow numeric columns compare through a widened view.
+		if get, ok := array.Int64Accessor(col); ok {
+			r, ok := literalToInt64(e.right)
+			if !ok {
+				return Mask{}, fmt.Errorf("cannot compare %s column with literal", col.DType())
+			}
+			for i := range vals {
+				if col.IsNull(i) {
+					continue
+				}
+				valid[i] = true
+				vals[i] = cmpInt64(e.op, get(i), r)
+			}
+		} else if get, ok := array.Float64Accessor(col); ok {
+			r, ok := literalToFloat64(e.right)
+			if !ok {
+				return Mask{}, fmt.Errorf("cannot compare %s column with literal", col.DType())
+			}
+			for i := range vals {
+				if col.IsNull(i) {
+					continue
+				}
+				valid[i] = true
+				vals[i] = cmpFloat64(e.op, get(i), r)
+			}
+		} else {
+			return Mask{}, fmt.Errorf("unsupported compare column type")
+		}
 	}
 	return Mask{Data: vals, Valid: valid}, nil
 }
@@ -283,6 +393,7 @@ func (e evenExpr) Eval(f Frame) (Mask, error) {
 	}
 	vals := make([]bool, col.Len())
 	valid := make([]bool, col.Len())
+	ints, isInt := array.Int64Accessor(col)
 	for i := range vals {
 		if col.IsNull(i) {
 			continue
@@ -298,7 +409,7 @@ func (e evenExpr) Eval(f Frame) (Mask, error) {
 		case *array.BoolColumn:
 			vals[i] = !c.Value(i)
 		default:
-			vals[i] = false
+			vals[i] = isInt && ints(i)%2 == 0
 		}
 	}
 	return Mask{Data: vals, Valid: valid}, nil
@@ -392,6 +503,10 @@ func ExprColumns(e Expr) []string {
 	switch x := e.(type) {
 	case compareExpr:
 		return []string{x.left}
+	case valueCompareExpr:
+		return unionColumns(ValueColumns(x.left), ValueColumns(x.right))
+	case strPredicate:
+		return ValueColumns(x.child)
 	case inExpr:
 		return []string{x.col}
 	case evenExpr:
@@ -401,30 +516,32 @@ func ExprColumns(e Expr) []string {
 	case notExpr:
 		return ExprColumns(x.child)
 	case logicalExpr:
-		l := ExprColumns(x.left)
-		r := ExprColumns(x.right)
-		out := make([]string, 0, len(l)+len(r))
-		seen := map[string]struct{}{}
-		for i := range l {
-			if _, ok := seen[l[i]]; ok {
-				continue
-			}
-			seen[l[i]] = struct{}{}
-			out = append(out, l[i])
-		}
-		for i := range r {
-			if _, ok := seen[r[i]]; ok {
-				continue
-			}
-			seen[r[i]] = struct{}{}
-			out = append(out, r[i])
-		}
-		return out
+		return unionColumns(ExprColumns(x.left), ExprColumns(x.right))
 	default:
 		return nil
 	}
 }
 
+func unionColumns(l, r []string) []string {
+	out := make([]string, 0, len(l)+len(r))
+	seen := map[string]struct{}{}
+	for i := range l {
+		if _, ok := seen[l[i]]; ok {
+			continue
+		}
+		seen[l[i]] = struct{}{}
+		out = append(out, l[i])
+	}
+	for i := range r {
+		if _, ok := seen[r[i]]; ok {
+			continue
+		}
+		seen[r[i]] = struct{}{}
+		out = append(out, r[i])
+	}
+	return out
+}
+
 func IsEven(e Expr) (string, bool) {
 	x, ok := e.(evenExpr)
 	if !ok {
@@ -441,6 +558,12 @@ func literalToInt64(v any) (int64, bool) {
 		return x, true
 	case int32:
 		return int64(x), true
+	case uint32:
+		return int64(x), true
+	case uint:
+		return int64(x), x <= math.MaxInt64
+	case uint64:
+		return int64(x), x <= math.MaxInt64
 	case float64:
 		return int64(x), true
 	case string:
@@ -451,12 +574,60 @@ func literalToInt64(v any) (int64, bool) {
 	}
 }
 
+// literalToUint64 converts integer literals that fit in a uint64. Floats are
+// not truncated, unlike literalToInt64, so they can compare as float64.
+func literalToUint64(v any) (uint64, bool) {
+	switch x := v.(type) {
+	case int:
+		return uint64(x), x >= 0
+	case int64:
+		return uint64(x), x >= 0
+	case int32:
+		return uint64(x), x >= 0
+	case uint:
+		return uint64(x), true
+	case uint64:
+		return x, true
+	case uint32:
+		return uint64(x), true
+	case string:
+		n, err := strconv.ParseUint(x, 10, 64)
+		return n, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// uint64LiteralOrder returns a function ordering uint64 values against the
+// literal v. Integer literals, negative ones included, order exactly.
+func uint64LiteralOrder(v any) (func(uint64) int, bool) {
+	if r, ok := literalToUint64(v); ok {
+		return func(a uint64) int { return cmp.Compare(a, r) }, true
+	}
+	switch v.(type) {
+	case int, int64, int32:
+		// literalToUint64 rejected it, so it is negative.
+		return func(uint64) int { return 1 }, true
+	}
+	if s, ok := v.(string); ok {
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil && n < 0 {
+			return func(uint64) int { return 1 }, true
+		}
+	}
+	if r, ok := literalToFloat64(v); ok {
+		return func(a uint64) int { return cmp.Compare(float64(a), r) }, true
+	}
+	return nil, false
+}
+
 func literalToFloat64(v any) (float64, bool) {
 	switch x := v.(type) {
 	case int:
 		return float64(x), true
 	case int64:
 		return float64(x), true
+	case uint64:
+		return float64(x), true
 	case float64:
 		return x, true
 	case string:
@@ -466,3 +637,39 @@ func literalToFloat64(v any) (float64, bool) {
 		return 0, false
 	}
 }
+
+func literalToDate(v any) (int32, bool) {
+	switch x := v.(type) {
+	case time.Time:
+		return array.DateFromTime(x), true
+	case string:
+		d, err := array.ParseDate(x)
+		return d, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func literalToDatetime(v any, unit array.TimeUnit, loc *time.Location) (int64, bool) {
+	switch x := v.(type) {
+	case time.Time:
+		return array.TimeToUnit(x, unit), true
+	case string:
+		n, err := array.ParseDatetime(x, unit, loc)
+		return n, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func literalToDuration(v any, unit array.TimeUnit) (int64, bool) {
+	switch x := v.(type) {
+	case time.Duration:
+		return array.DurationToUnit(x, unit), true
+	case string:
+		d, err := time.ParseDuration(x)
+		return array.DurationToUnit(d, unit), err == nil
+	default:
+		return 0, false
+	}
+}
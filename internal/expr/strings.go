@@ -0,0 +1,349 @@
+package expr
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"unicode"
+	"unicode/utf8"
+
+	"grizzly/internal/array"
+)
+
+type strPredOp uint8
+
+const (
+	strContains strPredOp = iota + 1
+	strStartsWith
+	strEndsWith
+	strMatches
+)
+
+func (op strPredOp) String() string {
+	switch op {
+	case strContains:
+		return "contains"
+	case strStartsWith:
+		return "starts_with"
+	case strEndsWith:
+		return "ends_with"
+	case strMatches:
+		return "matches"
+	default:
+		return "?"
+	}
+}
+
+// StrContains, StrStartsWith and StrEndsWith test utf8 values for a literal
+// substring. StrMatches tests them against a regular expression (RE2
+// syntax); an invalid pattern is reported when the expression is evaluated.
+// All four work on the column's byte buffer without building Go strings.
+func StrContains(v Value, sub string) Expr {
+	return strPredicate{child: v, op: strContains, pat: []byte(sub)}
+}
+func StrStartsWith(v Value, prefix string) Expr {
+	return strPredicate{child: v, op: strStartsWith, pat: []byte(prefix)}
+}
+func StrEndsWith(v Value, suffix string) Expr {
+	return strPredicate{child: v, op: strEndsWith, pat: []byte(suffix)}
+}
+func StrMatches(v Value, pattern string) Expr {
+	re, err := regexp.Compile(pattern)
+	return strPredicate{child: v, op: strMatches, re: re, err: err}
+}
+
+type strPredicate struct {
+	child Value
+	op    strPredOp
+	pat   []byte
+	re    *regexp.Regexp
+	err   error
+}
+
+func (e strPredicate) Eval(f Frame) (Mask, error) {
+	if e.err != nil {
+		return Mask{}, fmt.Errorf("str.%s: %w", e.op, e.err)
+	}
+	c, err := evalUtf8(e.child, f, e.op.String())
+	if err != nil {
+		return Mask{}, err
+	}
+	buf := c.Bytes()
+	vals := make([]bool, c.Len())
+	valid := make([]bool, c.Len())
+	for i := range vals {
+		if c.IsNull(i) {
+			continue
+		}
+		valid[i] = true
+		start, end := c.ByteRange(i)
+		s := buf[start:end]
+		switch e.op {
+		case strContains:
+			vals[i] = bytes.Contains(s, e.pat)
+		case strStartsWith:
+			vals[i] = bytes.HasPrefix(s, e.pat)
+		case strEndsWith:
+			vals[i] = bytes.HasSuffix(s, e.pat)
+		case strMatches:
+			vals[i] = e.re.Match(s)
+		}
+	}
+	return Mask{Data: vals, Valid: valid}, nil
+}
+
+func evalUtf8(v Value, f Frame, fn string) (*array.Utf8Column, error) {
+	col, err := v.EvalValue(f)
+	if err != nil {
+		return nil, err
+	}
+	c, ok := col.(*array.Utf8Column)
+	if !ok {
+		return nil, fmt.Errorf("str.%s requires utf8, got %s for %s", fn, col.DType(), col.Name())
+	}
+	return c, nil
+}
+
+// StrLen is the length of each utf8 value as int64, in bytes or, with
+// chars set, in Unicode code points.
+func StrLen(v Value, chars bool) Value { return strLenExpr{child: v, chars: chars} }
+
+type strLenExpr struct {
+	child Value
+	chars bool
+}
+
+func (e strLenExpr) EvalValue(f Frame) (array.Column, error) {
+	c, err := evalUtf8(e.child, f, "len")
+	if err != nil {
+		return nil, err
+	}
+	buf := c.Bytes()
+	out := make([]int64, c.Len())
+	for i := range out {
+		if c.IsNull(i) {
+			continue
+		}
+		start, end := c.ByteRange(i)
+		if e.chars {
+			out[i] = int64(utf8.RuneCount(buf[start:end]))
+		} else {
+			out[i] = int64(end - start)
+		}
+	}
+	return array.NewInt64ColumnOwned(OutputName(e), out, c.Validity()), nil
+}
+
+type strMapOp uint8
+
+const (
+	strLower strMapOp = iota + 1
+	strUpper
+	strStrip
+	strSlice
+	strReplace
+	strSplitPart
+)
+
+func (op strMapOp) String() string {
+	switch op {
+	case strLower:
+		return "lower"
+	case strUpper:
+		return "upper"
+	case strStrip:
+		return "strip"
+	case strSlice:
+		return "slice"
+	case strReplace:
+		return "replace"
+	case strSplitPart:
+		return "split"
+	default:
+		return "?"
+	}
+}
+
+// StrLower and StrUpper map every code point with unicode.ToLower/ToUpper.
+func StrLower(v Value) Value { return strMapExpr{child: v, op: strLower} }
+func StrUpper(v Value) Value { return strMapExpr{child: v, op: strUpper} }
+
+// StrStrip removes leading and trailing code points contained in chars, or
+// Unicode white space when chars is empty.
+func StrStrip(v Value, chars string) Value { return strMapExpr{child: v, op: strStrip, a: chars} }
+
+// StrSlice takes length code points starting at offset. A negative offset
+// counts from the end; a negative length runs to the end.
+func StrSlice(v Value, offset, length int) Value {
+	return strMapExpr{child: v, op: strSlice, n: offset, m: length}
+}
+
+// StrReplace replaces every occurrence of old with with.
+func StrReplace(v Value, old, with string) Value {
+	return strMapExpr{child: v, op: strReplace, a: old, b: with}
+}
+
+// StrSplitPart splits on sep and keeps the part at index, or NULL when there
+// are not that many parts.
+func StrSplitPart(v Value, sep string, index int) Value {
+	return strMapExpr{child: v, op: strSplitPart, a: sep, n: index}
+}
+
+type strMapExpr struct {
+	child Value
+	op    strMapOp
+	a, b  string
+	n, m  int
+}
+
+func (e strMapExpr) EvalValue(f Frame) (array.Column, error) {
+	c, err := evalUtf8(e.child, f, e.op.String())
+	if err != nil {
+		return nil, err
+	}
+	if e.op == strSplitPart && e.a == "" {
+		return nil, fmt.Errorf("str.split: empty separator")
+	}
+	src := c.Bytes()
+	n := c.Len()
+	offsets := make([]int32, 1, n+1)
+	buf := make([]byte, 0, len(src))
+	valid := array.BitmapBuilder{}
+	valid.Reserve(n)
+	for i := 0; i < n; i++ {
+		ok := !c.IsNull(i)
+		if ok {
+			start, end := c.ByteRange(i)
+			buf, ok = e.apply(buf, src[start:end])
+		}
+		offsets = append(offsets, int32(len(buf)))
+		valid.Append(ok)
+	}
+	return array.NewUtf8ColumnOwned(OutputName(e), offsets, buf, valid.Build()), nil
+}
+
+// apply appends the result for s to dst; false means NULL.
+func (e strMapExpr) apply(dst, s []byte) ([]byte, bool) {
+	switch e.op {
+	case strLower, strUpper:
+		for len(s) > 0 {
+			r, size := utf8.DecodeRune(s)
+			if e.op == strLower {
+				r = unicode.ToLower(r)
+			} else {
+				r = unicode.ToUpper(r)
+			}
+			dst = utf8.AppendRune(dst, r)
+			s = s[size:]
+		}
+		return dst, true
+	case strStrip:
+		if e.a == "" {
+			return append(dst, bytes.TrimSpace(s)...), true
+		}
+		return append(dst, bytes.Trim(s, e.a)...), true
+	case strSlice:
+		start, length := e.n, e.m
+		if start < 0 {
+			start = max(utf8.RuneCount(s)+start, 0)
+		}
+		for ; start > 0 && len(s) > 0; start-- {
+			_, size := utf8.DecodeRune(s)
+			s = s[size:]
+		}
+		if length < 0 {
+			return append(dst, s...), true
+		}
+		end := 0
+		for ; length > 0 && end < len(s); length-- {
+			_, size := utf8.DecodeRune(s[end:])
+			end += size
+		}
+		return append(dst, s[:end]...), true
+	case strReplace:
+		if e.a == "" {
+			return append(dst, s...), true
+		}
+		old := []byte(e.a)
+		for {
+			j := bytes.Index(s, old)
+			if j < 0 {
+				return append(dst, s...), true
+			}
+			dst = append(dst, s[:j]...)
+			dst = append(dst, e.b...)
+			s = s[j+len(old):]
+		}
+	case strSplitPart:
+		sep := []byte(e.a)
+		for k := 0; ; k++ {
+			j := bytes.Index(s, sep)
+			if k == e.n {
+				if j < 0 {
+					return append(dst, s...), true
+				}
+				return append(dst, s[:j]...), true
+			}
+			if j < 0 || e.n < 0 {
+				return dst, false
+			}
+			s = s[j+len(sep):]
+		}
+	default:
+		return dst, false
+	}
+}
+
+// ConcatStr joins the values of every row with sep. Non-utf8 values are
+// formatted as by Cast to utf8. A NULL in any input gives NULL.
+func ConcatStr(sep string, vals ...Value) Value {
+	return concatStrExpr{sep: sep, vals: append([]Value(nil), vals...)}
+}
+
+type concatStrExpr struct {
+	sep  string
+	vals []Value
+}
+
+func (e concatStrExpr) EvalValue(f Frame) (array.Column, error) {
+	if len(e.vals) == 0 {
+		return nil, fmt.Errorf("str.concat: no inputs")
+	}
+	cols := make([]*array.Utf8Column, len(e.vals))
+	for i, v := range e.vals {
+		col, err := v.EvalValue(f)
+		if err != nil {
+			return nil, err
+		}
+		if col, err = array.Cast(col, array.Utf8()); err != nil {
+			return nil, err
+		}
+		cols[i] = col.(*array.Utf8Column)
+	}
+	n := f.Height()
+	offsets := make([]int32, 1, n+1)
+	var buf []byte
+	valid := array.BitmapBuilder{}
+	valid.Reserve(n)
+	for i := 0; i < n; i++ {
+		ok := true
+		for _, c := range cols {
+			if c.IsNull(i) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			for k, c := range cols {
+				if k > 0 {
+					buf = append(buf, e.sep...)
+				}
+				start, end := c.ByteRange(i)
+				buf = append(buf, c.Bytes()[start:end]...)
+			}
+		}
+		offsets = append(offsets, int32(len(buf)))
+		valid.Append(ok)
+	}
+	return array.NewUtf8ColumnOwned(OutputName(e), offsets, buf, valid.Build()), nil
+}
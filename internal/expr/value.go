@@ -0,0 +1,428 @@
+package expr
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"grizzly/internal/array"
+)
+
+// Value is an expression that evaluates to a column with one row per frame
+// row, as opposed to Expr, which evaluates to a filter mask.
+type Value interface {
+	EvalValue(f Frame) (array.Column, error)
+}
+
+func (c ColRef) EvalValue(f Frame) (array.Column, error) {
+	col, ok := f.Column(c.Name)
+	if !ok {
+		return nil, fmt.Errorf("unknown column %s", c.Name)
+	}
+	return col, nil
+}
+
+type arithOp uint8
+
+const (
+	arithAdd arithOp = iota + 1
+	arithSub
+	arithMul
+	arithDiv
+	arithMod
+)
+
+func (op arithOp) String() string {
+	switch op {
+	case arithAdd:
+		return "+"
+	case arithSub:
+		return "-"
+	case arithMul:
+		return "*"
+	case arithDiv:
+		return "/"
+	case arithMod:
+		return "%"
+	default:
+		return "?"
+	}
+}
+
+// Add, Sub, Mul, Div and Mod combine two numeric values row by row.
+//
+// Promotion: int64 op int64 is int64, except Div, which always produces
+// float64; any float64 operand makes the result float64. uint64 op uint64
+// (or a non-negative integer literal) is uint64 and wraps like Go's uint64;
+// uint64 mixed with signed columns computes as float64. A NULL on either
+// side gives NULL, as does integer Mod by zero. Integer Mod keeps the sign of
+// the dividend, like Go's %. Float division follows IEEE 754.
+func Add(a, b Value) Value { return arithExpr{left: a, right: b, op: arithAdd} }
+func Sub(a, b Value) Value { return arithExpr{left: a, right: b, op: arithSub} }
+func Mul(a, b Value) Value { return arithExpr{left: a, right: b, op: arithMul} }
+func Div(a, b Value) Value { return arithExpr{left: a, right: b, op: arithDiv} }
+func Mod(a, b Value) Value { return arithExpr{left: a, right: b, op: arithMod} }
+
+// Neg negates a numeric value.
+func Neg(v Value) Value { return negExpr{child: v} }
+
+// Lit is a constant broadcast to every row. Supported Go types are int,
+// int32, int64, uint64, float32, float64, bool, string, time.Time (naive datetime in
+// microseconds) and time.Duration (microseconds).
+func Lit(v any) Value { return litExpr{value: v} }
+
+// Cast converts a value to dtype. Values that do not convert become NULL,
+// or with strict fail the evaluation; see array.Cast and array.CastStrict.
+func Cast(v Value, dtype array.DataType, strict bool) Value {
+	return castExpr{child: v, dtype: dtype, strict: strict}
+}
+
+// Alias renames the result of a value expression.
+func Alias(v Value, name string) Value { return aliasExpr{child: v, name: name} }
+
+// OutputName is the column name a value produces: the alias if any, else the
+// name of its leftmost column reference, else "literal".
+func OutputName(v Value) string {
+	switch x := v.(type) {
+	case ColRef:
+		return x.Name
+	case aliasExpr:
+		return x.name
+	case arithExpr:
+		return OutputName(x.left)
+	case negExpr:
+		return OutputName(x.child)
+	case castExpr:
+		return OutputName(x.child)
+	case strLenExpr:
+		return OutputName(x.child)
+	case strMapExpr:
+		return OutputName(x.child)
+	case concatStrExpr:
+		if len(x.vals) > 0 {
+			return OutputName(x.vals[0])
+		}
+		return "literal"
+	case whenExpr:
+		if len(x.thens) > 0 {
+			return OutputName(x.thens[0])
+		}
+		return "literal"
+	case coalesceExpr:
+		if len(x.vals) > 0 {
+			return OutputName(x.vals[0])
+		}
+		return "literal"
+	case fillNullExpr:
+		return OutputName(x.child)
+	default:
+		return "literal"
+	}
+}
+
+// ValueColumns returns the input columns referenced by v.
+func ValueColumns(v Value) []string {
+	var out []string
+	seen := map[string]struct{}{}
+	var walk func(v Value)
+	walk = func(v Value) {
+		switch x := v.(type) {
+		case ColRef:
+			if _, ok := seen[x.Name]; !ok {
+				seen[x.Name] = struct{}{}
+				out = append(out, x.Name)
+			}
+		case arithExpr:
+			walk(x.left)
+			walk(x.right)
+		case negExpr:
+			walk(x.child)
+		case castExpr:
+			walk(x.child)
+		case aliasExpr:
+			walk(x.child)
+		case strLenExpr:
+			walk(x.child)
+		case strMapExpr:
+			walk(x.child)
+		case concatStrExpr:
+			for _, v := range x.vals {
+				walk(v)
+			}
+		case whenExpr:
+			for i := range x.conds {
+				for _, c := range ExprColumns(x.conds[i]) {
+					walk(ColRef{Name: c})
+				}
+			}
+			for _, v := range x.thens {
+				walk(v)
+			}
+			if x.otherwise != nil {
+				walk(x.otherwise)
+			}
+		case coalesceExpr:
+			for _, v := range x.vals {
+				walk(v)
+			}
+		case fillNullExpr:
+			walk(x.child)
+			if x.fill != nil {
+				walk(x.fill)
+			}
+		}
+	}
+	walk(v)
+	return out
+}
+
+type arithExpr struct {
+	left  Value
+	right Value
+	op    arithOp
+}
+
+type negExpr struct {
+	child Value
+}
+
+type litExpr struct {
+	value any
+}
+
+type castExpr struct {
+	child  Value
+	dtype  array.DataType
+	strict bool
+}
+
+type aliasExpr struct {
+	child Value
+	name  string
+}
+
+// numeric is a row accessor over an int64, uint64 or float64 operand.
+// Literals are kept as scalars instead of being broadcast. ints is nil for
+// operands outside int64, and uints is nil outside uint64.
+type numeric struct {
+	isFloat bool
+	isUint  bool
+	ints    func(int) int64
+	uints   func(int) uint64
+	floats  func(int) float64
+	null    func(int) bool
+}
+
+func numericOperand(v Value, f Frame) (numeric, error) {
+	if lit, ok := v.(litExpr); ok {
+		switch x := lit.value.(type) {
+		case int, int32, int64:
+			n, _ := literalToInt64(x)
+			v := numeric{ints: func(int) int64 { return n }, floats: func(int) float64 { return float64(n) }, null: func(int) bool { return false }}
+			if n >= 0 {
+				v.uints = func(int) uint64 { return uint64(n) }
+			}
+			return v, nil
+		case uint64:
+			v := numeric{isUint: true, uints: func(int) uint64 { return x }, floats: func(int) float64 { return float64(x) }, null: func(int) bool { return false }}
+			if x <= math.MaxInt64 {
+				v.ints = func(int) int64 { return int64(x) }
+			}
+			return v, nil
+		case float32:
+			return numeric{isFloat: true, floats: func(int) float64 { return float64(x) }, null: func(int) bool { return false }}, nil
+		case float64:
+			return numeric{isFloat: true, floats: func(int) float64 { return x }, null: func(int) bool { return false }}, nil
+		}
+	}
+	col, err := v.EvalValue(f)
+	if err != nil {
+		return numeric{}, err
+	}
+	// Narrow integers widen to int64 and float32 computes as float64.
+	if c, ok := col.(*array.UInt64Column); ok {
+		return numeric{isUint: true, uints: c.Value, floats: func(i int) float64 { return float64(c.Value(i)) }, null: col.IsNull}, nil
+	}
+	if ints, ok := array.Int64Accessor(col); ok {
+		return numeric{ints: ints, floats: func(i int) float64 { return float64(ints(i)) }, null: col.IsNull}, nil
+	}
+	if floats, ok := array.Float64Accessor(col); ok {
+		return numeric{isFloat: true, floats: floats, null: col.IsNull}, nil
+	}
+	return numeric{}, fmt.Errorf("arithmetic requires numeric operands, got %s for %s", col.DType(), col.Name())
+}
+
+func (e arithExpr) EvalValue(f Frame) (array.Column, error) {
+	l, err := numericOperand(e.left, f)
+	if err != nil {
+		return nil, err
+	}
+	r, err := numericOperand(e.right, f)
+	if err != nil {
+		return nil, err
+	}
+	n := f.Height()
+	name := OutputName(e)
+	valid := make([]bool, n)
+
+	if (l.isUint || r.isUint) && l.uints != nil && r.uints != nil && e.op != arithDiv {
+		out := make([]uint64, n)
+		for i := range out {
+			if l.null(i) || r.null(i) {
+				continue
+			}
+			a, b := l.uints(i), r.uints(i)
+			switch e.op {
+			case arithAdd:
+				out[i] = a + b
+			case arithSub:
+				out[i] = a - b
+			case arithMul:
+				out[i] = a * b
+			case arithMod:
+				if b == 0 {
+					continue
+				}
+				out[i] = a % b
+			}
+			valid[i] = true
+		}
+		return array.NewUInt64ColumnOwned(name, out, array.NewBitmapFromBools(valid)), nil
+	}
+
+	if l.ints != nil && r.ints != nil && e.op != arithDiv {
+		out := make([]int64, n)
+		for i := range out {
+			if l.null(i) || r.null(i) {
+				continue
+			}
+			a, b := l.ints(i), r.ints(i)
+			switch e.op {
+			case arithAdd:
+				out[i] = a + b
+			case arithSub:
+				out[i] = a - b
+			case arithMul:
+				out[i] = a * b
+			case arithMod:
+				if b == 0 {
+					continue
+				}
+				out[i] = a % b
+			}
+			valid[i] = true
+		}
+		return array.NewInt64ColumnOwned(name, out, array.NewBitmapFromBools(valid)), nil
+	}
+
+	out := make([]float64, n)
+	for i := range out {
+		if l.null(i) || r.null(i) {
+			continue
+		}
+		a, b := l.floats(i), r.floats(i)
+		switch e.op {
+		case arithAdd:
+			out[i] = a + b
+		case arithSub:
+			out[i] = a - b
+		case arithMul:
+			out[i] = a * b
+		case arithDiv:
+			out[i] = a / b
+		case arithMod:
+			out[i] = math.Mod(a, b)
+		}
+		valid[i] = true
+	}
+	return array.NewFloat64ColumnOwned(name, out, array.NewBitmapFromBools(valid)), nil
+}
+
+func (e negExpr) EvalValue(f Frame) (array.Column, error) {
+	v, err := numericOperand(e.child, f)
+	if err != nil {
+		return nil, err
+	}
+	n := f.Height()
+	name := OutputName(e)
+	valid := make([]bool, n)
+	if v.ints == nil {
+		out := make([]float64, n)
+		for i := range out {
+			if !v.null(i) {
+				out[i] = -v.floats(i)
+				valid[i] = true
+			}
+		}
+		return array.NewFloat64ColumnOwned(name, out, array.NewBitmapFromBools(valid)), nil
+	}
+	out := make([]int64, n)
+	for i := range out {
+		if !v.null(i) {
+			out[i] = -v.ints(i)
+			valid[i] = true
+		}
+	}
+	return array.NewInt64ColumnOwned(name, out, array.NewBitmapFromBools(valid)), nil
+}
+
+func (e litExpr) EvalValue(f Frame) (array.Column, error) {
+	n := f.Height()
+	all := array.NewBitmap(n, true)
+	switch x := e.value.(type) {
+	case int, int32, int64:
+		v, _ := literalToInt64(x)
+		return array.NewInt64ColumnOwned("literal", fill(n, v), all), nil
+	case uint64:
+		return array.NewUInt64ColumnOwned("literal", fill(n, x), all), nil
+	case float32:
+		return array.NewFloat64ColumnOwned("literal", fill(n, float64(x)), all), nil
+	case float64:
+		return array.NewFloat64ColumnOwned("literal", fill(n, x), all), nil
+	case bool:
+		return array.NewBoolColumnOwned("literal", fill(n, x), all), nil
+	case string:
+		offsets := make([]int32, n+1)
+		buf := make([]byte, 0, n*len(x))
+		for i := 0; i < n; i++ {
+			buf = append(buf, x...)
+			offsets[i+1] = int32(len(buf))
+		}
+		return array.NewUtf8ColumnOwned("literal", offsets, buf, all), nil
+	case time.Time:
+		v := array.TimeToUnit(x, array.TimeUnitUS)
+		return array.NewDatetimeColumnOwned("literal", fill(n, v), all, array.TimeUnitUS, ""), nil
+	case time.Duration:
+		v := array.DurationToUnit(x, array.TimeUnitUS)
+		return array.NewDurationColumnOwned("literal", fill(n, v), all, array.TimeUnitUS), nil
+	default:
+		return nil, fmt.Errorf("unsupported literal type %T", e.value)
+	}
+}
+
+func fill[T any](n int, v T) []T {
+	out := make([]T, n)
+	for i := range out {
+		out[i] = v
+	}
+	return out
+}
+
+func (e castExpr) EvalValue(f Frame) (array.Column, error) {
+	col, err := e.child.EvalValue(f)
+	if err != nil {
+		return nil, err
+	}
+	if e.strict {
+		return array.CastStrict(col, e.dtype)
+	}
+	return array.Cast(col, e.dtype)
+}
+
+func (e aliasExpr) EvalValue(f Frame) (array.Column, error) {
+	col, err := e.child.EvalValue(f)
+	if err != nil {
+		return nil, err
+	}
+	return array.WithName(col, e.name)
+}
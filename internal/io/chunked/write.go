@@ -0,0 +1,58 @@
+package chunked
+
+import (
+	"io"
+	"runtime"
+)
+
+// Write writes rows [0, n) to w in chunks of chunkRows, formatted by format.
+// With parallel, chunks are formatted on up to GOMAXPROCS workers and written
+// in row order as each completes, so at most a few chunks of output are held
+// in memory at once. The first write error stops the formatting of further
+// chunks and is returned.
+func Write(w io.Writer, n, chunkRows int, parallel bool, format func(start, end int) []byte) error {
+	chunks := (n + chunkRows - 1) / chunkRows
+	workers := 1
+	if parallel {
+		workers = min(runtime.GOMAXPROCS(0), chunks)
+	}
+	if workers <= 1 {
+		for start := 0; start < n; start += chunkRows {
+			if _, err := w.Write(format(start, min(start+chunkRows, n))); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// Each chunk gets a result channel, queued in row order. The queue's
+	// capacity bounds how many chunks are formatted ahead of the writer.
+	// done stops the producer once a write fails; result channels are
+	// buffered, so chunks already started finish without a reader.
+	queue := make(chan chan []byte, workers)
+	done := make(chan struct{})
+	go func() {
+		defer close(queue)
+		for start := 0; start < n; start += chunkRows {
+			out := make(chan []byte, 1)
+			select {
+			case <-done:
+				return
+			default:
+			}
+			select {
+			case queue <- out:
+			case <-done:
+				return
+			}
+			go func(start, end int) { out <- format(start, end) }(start, min(start+chunkRows, n))
+		}
+	}()
+	for out := range queue {
+		if _, err := w.Write(<-out); err != nil {
+			close(done)
+			return err
+		}
+	}
+	return nil
+}
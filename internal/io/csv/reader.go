@@ -10,11 +10,13 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"os"
 
 	"grizzly/internal/array"
 	"grizzly/internal/exec"
+	"grizzly/internal/expr"
 )
 
 const typeSampleRows = 8192
@@ -23,6 +25,28 @@ const chunkRows = 4096
 type ReadPlan struct {
 	Projection map[string]struct{}
 	FilterEven string
+	// Predicate is evaluated on every parsed chunk; only matching rows are
+	// kept, so it must be row-local (see expr.IsRowLocal). Columns it reads
+	// need not be in Projection.
+	Predicate expr.Expr
+	// MaxRows, when positive, lets the reader stop once that many rows have
+	// passed the filters. It may still return more rows than that.
+	MaxRows int
+	// DateFormats and DatetimeFormats map column names to Go time layouts.
+	// Listed columns skip inference and are parsed with the given layout.
+	DateFormats     map[string]string
+	DatetimeFormats map[string]string
+	// DTypes forces the named columns to a type instead of inferring it,
+	// e.g. int32 or uint8 to keep narrow values at their native width.
+	// Values that do not fit the type fail the read.
+	DTypes map[string]array.DataType
+}
+
+// columnSpec is the resolved parse target of one scanned column.
+// layout is set only for temporal columns with an explicit format.
+type columnSpec struct {
+	dtype  array.DataType
+	layout string
 }
 
 type NullMatcher struct {
@@ -157,6 +181,27 @@ func (b *utf8Builder) Reserve(addRows int, addBytes int) {
 	}
 }
 
+// ReadHeader returns the column names of a CSV file without reading any rows.
+func ReadHeader(path string, delimiter rune) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r := csv.NewReader(f)
+	if delimiter != 0 {
+		r.Comma = delimiter
+	}
+	header, err := r.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("empty csv")
+		}
+		return nil, err
+	}
+	return header, nil
+}
+
 func Read(ctx context.Context, path string, delimiter rune, nullValues []string, plan ReadPlan) (*exec.DataFrame, error) {
 	if ctx == nil {
 		ctx = context.Background()
@@ -205,24 +250,38 @@ func Read(ctx context.Context, path string, delimiter rune, nullValues []string,
 		headerIdx[header[i]] = i
 	}
 
+	predCols := map[string]struct{}{}
+	if plan.Predicate != nil {
+		for _, c := range expr.ExprColumns(plan.Predicate) {
+			if _, ok := headerIdx[c]; !ok {
+				return nil, fmt.Errorf("unknown filter column %s", c)
+			}
+			predCols[c] = struct{}{}
+		}
+	}
+
 	included := make([]int, 0, len(header))
 	includedNames := make([]string, 0, len(header))
-	if len(plan.Projection) == 0 {
-		for i := range header {
-			included = append(included, i)
-			includedNames = append(includedNames, header[i])
-		}
-	} else {
-		for i := range header {
-			if _, ok := plan.Projection[header[i]]; ok {
-				included = append(included, i)
-				includedNames = append(includedNames, header[i])
-			}
+	// output marks included columns that are returned rather than read only
+	// for the predicate.
+	output := make([]bool, 0, len(header))
+	outputs := 0
+	for i := range header {
+		_, projected := plan.Projection[header[i]]
+		projected = projected || len(plan.Projection) == 0
+		if _, ok := predCols[header[i]]; !ok && !projected {
+			continue
 		}
-		if len(included) == 0 {
-			return nil, fmt.Errorf("projection selected no columns")
+		included = append(included, i)
+		includedNames = append(includedNames, header[i])
+		output = append(output, projected)
+		if projected {
+			outputs++
 		}
 	}
+	if outputs == 0 {
+		return nil, fmt.Errorf("projection selected no columns")
+	}
 
 	filterIdx := -1
 	if plan.FilterEven != "" {
@@ -268,15 +327,17 @@ func Read(ctx context.Context, path string, delimiter rune, nullValues []string,
 		records = append(records, projected)
 	}
 
-	dtypes := make([]array.DataType, len(included))
+	specs := make([]columnSpec, len(included))
 	for i := range included {
-		dtypes[i] = inferType(samples[i], nulls)
+		if specs[i], err = resolveSpec(includedNames[i], samples[i], nulls, plan); err != nil {
+			return nil, err
+		}
 	}
 
 	seedRows := len(records) + chunkRows
 	builders := make([]typedBuilder, len(included))
 	for i := range included {
-		builders[i] = newBuilder(dtypes[i], nulls, seedRows)
+		builders[i] = newBuilder(specs[i], nulls, seedRows)
 	}
 
 	row := 1
@@ -294,10 +355,40 @@ func Read(ctx context.Context, path string, delimiter rune, nullValues []string,
 		row++
 	}
 
-	if err := parseRemainingParallel(ctx, r, header, included, filterIdx, nulls, dtypes, builders, row); err != nil {
-		return nil, err
+	var filter *chunkFilter
+	if plan.Predicate != nil {
+		filter = &chunkFilter{pred: plan.Predicate, names: includedNames, output: output}
+		if err := filter.add(builders); err != nil {
+			return nil, err
+		}
+		for i := range builders {
+			builders[i] = newBuilder(specs[i], nulls, chunkRows)
+		}
+	}
+
+	// The type-sampling window is always read in full, so a limit never
+	// changes the inferred dtypes.
+	limit := plan.MaxRows
+	if limit > 0 {
+		if filter != nil {
+			limit -= filter.rows()
+		} else {
+			limit -= len(records)
+		}
+	}
+	if plan.MaxRows <= 0 || limit > 0 {
+		if err := parseRemainingParallel(ctx, r, header, included, filterIdx, nulls, specs, builders, row, filter, max(limit, 0)); err != nil {
+			return nil, err
+		}
 	}
 
+	if filter != nil {
+		cols, err := filter.concat()
+		if err != nil {
+			return nil, err
+		}
+		return exec.NewDataFrame(cols...)
+	}
 	cols := make([]array.Column, len(included))
 	for i := range cols {
 		cols[i] = builders[i].Build(includedNames[i])
@@ -305,18 +396,94 @@ func Read(ctx context.Context, path string, delimiter rune, nullValues []string,
 	return exec.NewDataFrame(cols...)
 }
 
+// chunkFilter applies a pushed-down predicate to parsed chunks, so rows that
+// fail it never reach the final columns. Columns read only for the predicate
+// are dropped once it has been evaluated.
+type chunkFilter struct {
+	pred   expr.Expr
+	names  []string
+	output []bool
+	// parts holds the surviving output columns of each chunk in file order.
+	parts [][]array.Column
+}
+
+// apply builds the chunk held by builders and returns its matching rows.
+// It is safe to call from several goroutines.
+func (cf *chunkFilter) apply(builders []typedBuilder) ([]array.Column, error) {
+	cols := make([]array.Column, len(builders))
+	for i := range builders {
+		cols[i] = builders[i].Build(cf.names[i])
+	}
+	df, err := exec.NewDataFrame(cols...)
+	if err != nil {
+		return nil, err
+	}
+	if df, err = df.Filter(cf.pred); err != nil {
+		return nil, err
+	}
+	out := make([]array.Column, 0, len(cols))
+	for i, c := range df.Columns() {
+		if cf.output[i] {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+func (cf *chunkFilter) add(builders []typedBuilder) error {
+	part, err := cf.apply(builders)
+	if err != nil {
+		return err
+	}
+	cf.parts = append(cf.parts, part)
+	return nil
+}
+
+// rows counts the rows kept so far.
+func (cf *chunkFilter) rows() int {
+	n := 0
+	for _, part := range cf.parts {
+		if len(part) > 0 {
+			n += part[0].Len()
+		}
+	}
+	return n
+}
+
+func (cf *chunkFilter) concat() ([]array.Column, error) {
+	if len(cf.parts) == 1 {
+		return cf.parts[0], nil
+	}
+	cols := make([]array.Column, len(cf.parts[0]))
+	chunks := make([]array.Column, len(cf.parts))
+	for j := range cols {
+		for p := range cf.parts {
+			chunks[p] = cf.parts[p][j]
+		}
+		col, err := array.Concat(chunks...)
+		if err != nil {
+			return nil, err
+		}
+		cols[j] = col
+	}
+	return cols, nil
+}
+
 type parseJob struct {
 	index int
 	rows  [][]string
 }
 
-func parseRemainingParallel(ctx context.Context, r *csv.Reader, header []string, included []int, filterIdx int, nulls NullMatcher, dtypes []array.DataType, builders []typedBuilder, startRow int) error {
+// parseRemainingParallel parses the rest of the file in chunks on all
+// workers. A positive limit stops reading once that many rows were kept.
+func parseRemainingParallel(ctx context.Context, r *csv.Reader, header []string, included []int, filterIdx int, nulls NullMatcher, specs []columnSpec, builders []typedBuilder, startRow int, filter *chunkFilter, limit int) error {
 	cancellable := ctx != nil && ctx.Done() != nil
 	const ctxCheckMask = 1024 - 1
 	workers := runtime.GOMAXPROCS(0)
 	if workers < 2 {
-		return parseRemainingSequential(ctx, r, header, included, filterIdx, nulls, builders, startRow)
+		return parseRemainingSequential(ctx, r, header, included, filterIdx, nulls, specs, builders, startRow, filter, limit)
 	}
+	kept := 0
 
 	chunk := make([][]string, 0, chunkRows)
 	chunkIndex := 0
@@ -331,10 +498,23 @@ func parseRemainingParallel(ctx context.Context, r *csv.Reader, header []string,
 				return err
 			}
 		}
-		results, err := parseBatch(ctx, batch, dtypes, nulls, startRow)
+		results, filtered, err := parseBatch(ctx, batch, specs, nulls, startRow, filter)
 		if err != nil {
 			return err
 		}
+		if filter != nil {
+			filter.parts = append(filter.parts, filtered...)
+			for _, part := range filtered {
+				if len(part) > 0 {
+					kept += part[0].Len()
+				}
+			}
+			batch = batch[:0]
+			return nil
+		}
+		for _, job := range batch {
+			kept += len(job.rows)
+		}
 		reserveForMerge(builders, results)
 		for _, res := range results {
 			for j := range builders {
@@ -383,12 +563,20 @@ func parseRemainingParallel(ctx context.Context, r *csv.Reader, header []string,
 			projected[i] = rec[src]
 		}
 		chunk = append(chunk, projected)
+		// Without a filter every row is kept, so the limit is known before
+		// parsing; with one, only after each batch.
+		if filter == nil && limit > 0 && kept+len(batch)*chunkRows+len(chunk) >= limit {
+			break
+		}
 		if len(chunk) >= chunkRows {
 			flushChunk()
 			if len(batch) >= workers*2 {
 				if err := flushBatch(); err != nil {
 					return err
 				}
+				if limit > 0 && kept >= limit {
+					return nil
+				}
 			}
 		}
 	}
@@ -399,14 +587,20 @@ func parseRemainingParallel(ctx context.Context, r *csv.Reader, header []string,
 	return nil
 }
 
-func parseBatch(ctx context.Context, batch []parseJob, dtypes []array.DataType, nulls NullMatcher, startRow int) ([][]typedBuilder, error) {
+// parseBatch parses every job in its own goroutine. With a filter, each job
+// is also filtered and the surviving columns are returned instead of builders.
+func parseBatch(ctx context.Context, batch []parseJob, specs []columnSpec, nulls NullMatcher, startRow int, filter *chunkFilter) ([][]typedBuilder, [][]array.Column, error) {
 	cancellable := ctx != nil && ctx.Done() != nil
 	if cancellable {
 		if err := ctx.Err(); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 	results := make([][]typedBuilder, len(batch))
+	var filtered [][]array.Column
+	if filter != nil {
+		filtered = make([][]array.Column, len(batch))
+	}
 	errCh := make(chan error, len(batch))
 	var wg sync.WaitGroup
 	for i := range batch {
@@ -415,10 +609,10 @@ func parseBatch(ctx context.Context, batch []parseJob, dtypes []array.DataType,
 			defer wg.Done()
 			const ctxCheckMask = 1024 - 1
 			job := batch[i]
-			local := make([]typedBuilder, len(dtypes))
+			local := make([]typedBuilder, len(specs))
 			rowsCap := len(job.rows)
-			for j := range dtypes {
-				local[j] = newBuilder(dtypes[j], nulls, rowsCap)
+			for j := range specs {
+				local[j] = newBuilder(specs[j], nulls, rowsCap)
 			}
 			row := startRow + job.index*chunkRows
 			for r := range job.rows {
@@ -435,15 +629,24 @@ func parseBatch(ctx context.Context, batch []parseJob, dtypes []array.DataType,
 					}
 				}
 			}
+			if filter != nil {
+				cols, err := filter.apply(local)
+				if err != nil {
+					errCh <- err
+					return
+				}
+				filtered[i] = cols
+				return
+			}
 			results[i] = local
 		}(i)
 	}
 	wg.Wait()
 	close(errCh)
 	if err, ok := <-errCh; ok {
-		return nil, err
+		return nil, nil, err
 	}
-	return results, nil
+	return results, filtered, nil
 }
 
 func reserveForMerge(dst []typedBuilder, batch [][]typedBuilder) {
@@ -457,6 +660,8 @@ func reserveForMerge(dst []typedBuilder, batch [][]typedBuilder) {
 				bytes[j] += len(b.bytes)
 			case *genericBuilder[int64]:
 				rows[j] += len(b.data)
+			case *genericBuilder[int32]:
+				rows[j] += len(b.data)
 			case *genericBuilder[float64]:
 				rows[j] += len(b.data)
 			case *genericBuilder[bool]:
@@ -471,11 +676,16 @@ func reserveForMerge(dst []typedBuilder, batch [][]typedBuilder) {
 	}
 }
 
-func parseRemainingSequential(ctx context.Context, r *csv.Reader, header []string, included []int, filterIdx int, nulls NullMatcher, builders []typedBuilder, row int) error {
+func parseRemainingSequential(ctx context.Context, r *csv.Reader, header []string, included []int, filterIdx int, nulls NullMatcher, specs []columnSpec, builders []typedBuilder, row int, filter *chunkFilter, limit int) error {
 	cancellable := ctx != nil && ctx.Done() != nil
 	const ctxCheckMask = 1024 - 1
 	iter := 0
+	pending := 0
+	kept := 0
 	for {
+		if limit > 0 && kept >= limit {
+			break
+		}
 		iter++
 		if cancellable && (iter&ctxCheckMask) == 0 {
 			if err := ctx.Err(); err != nil {
@@ -501,37 +711,36 @@ func parseRemainingSequential(ctx context.Context, r *csv.Reader, header []strin
 			}
 		}
 		row++
+		pending++
+		if filter == nil {
+			kept++
+		}
+		if filter != nil && pending == chunkRows {
+			before := filter.rows()
+			if err := filter.add(builders); err != nil {
+				return err
+			}
+			kept += filter.rows() - before
+			for i := range builders {
+				builders[i] = newBuilder(specs[i], nulls, chunkRows)
+			}
+			pending = 0
+		}
+	}
+	if filter != nil && pending > 0 {
+		return filter.add(builders)
 	}
 	return nil
 }
 
-func newBuilder(dtype array.DataType, nulls NullMatcher, rowsCap int) typedBuilder {
+func newBuilder(spec columnSpec, nulls NullMatcher, rowsCap int) typedBuilder {
 	if rowsCap < 16 {
 		rowsCap = 16
 	}
+	dtype := spec.dtype
 	switch dtype.Kind {
-	case array.KindInt:
-		if dtype.Bits != 64 {
-			// For now, IO inference always maps ints to int64.
-			// Wider type support will be introduced via explicit Cast and FromStructs.
-		}
-		return &genericBuilder[int64]{
-			data:  make([]int64, 0, rowsCap),
-			nulls: nulls,
-			parse: func(raw string) (int64, error) { return strconv.ParseInt(raw, 10, 64) },
-			construct: func(name string, data []int64, valid array.Bitmap) array.Column {
-				return array.NewInt64ColumnOwned(name, data, valid)
-			},
-		}
-	case array.KindFloat:
-		return &genericBuilder[float64]{
-			data:  make([]float64, 0, rowsCap),
-			nulls: nulls,
-			parse: func(raw string) (float64, error) { return strconv.ParseFloat(raw, 64) },
-			construct: func(name string, data []float64, valid array.Bitmap) array.Column {
-				return array.NewFloat64ColumnOwned(name, data, valid)
-			},
-		}
+	case array.KindInt, array.KindUInt, array.KindFloat:
+		return newNumericBuilder(dtype, nulls, rowsCap)
 	case array.KindBool:
 		return &genericBuilder[bool]{
 			data:  make([]bool, 0, rowsCap),
@@ -541,6 +750,45 @@ func newBuilder(dtype array.DataType, nulls NullMatcher, rowsCap int) typedBuild
 				return array.NewBoolColumnOwned(name, data, valid)
 			},
 		}
+	case array.KindDate:
+		parse := array.ParseDate
+		if spec.layout != "" {
+			layout := spec.layout
+			parse = func(raw string) (int32, error) {
+				t, err := time.Parse(layout, raw)
+				if err != nil {
+					return 0, err
+				}
+				return array.DateFromTime(t), nil
+			}
+		}
+		return &genericBuilder[int32]{
+			data:      make([]int32, 0, rowsCap),
+			nulls:     nulls,
+			parse:     parse,
+			construct: array.NewDateColumnOwned,
+		}
+	case array.KindDatetime:
+		unit := dtype.Unit
+		parse := func(raw string) (int64, error) { return array.ParseDatetime(raw, unit, nil) }
+		if spec.layout != "" {
+			layout := spec.layout
+			parse = func(raw string) (int64, error) {
+				t, err := time.Parse(layout, raw)
+				if err != nil {
+					return 0, err
+				}
+				return array.TimeToUnit(t, unit), nil
+			}
+		}
+		return &genericBuilder[int64]{
+			data:  make([]int64, 0, rowsCap),
+			nulls: nulls,
+			parse: parse,
+			construct: func(name string, data []int64, valid array.Bitmap) array.Column {
+				return array.NewDatetimeColumnOwned(name, data, valid, dtype.Unit, dtype.TZ)
+			},
+		}
 	default:
 		byteCap := rowsCap * 16
 		if byteCap < 256 {
@@ -550,24 +798,159 @@ func newBuilder(dtype array.DataType, nulls NullMatcher, rowsCap int) typedBuild
 	}
 }
 
+// newNumericBuilder parses integers and floats at the width of dtype.
+func newNumericBuilder(dtype array.DataType, nulls NullMatcher, rowsCap int) typedBuilder {
+	switch dtype {
+	case array.Int(32):
+		return newIntBuilder(32, nulls, rowsCap, array.NewInt32ColumnOwned)
+	case array.Int(16):
+		return newIntBuilder(16, nulls, rowsCap, array.NewInt16ColumnOwned)
+	case array.Int(8):
+		return newIntBuilder(8, nulls, rowsCap, array.NewInt8ColumnOwned)
+	case array.UInt(64):
+		return newUintBuilder(64, nulls, rowsCap, array.NewUInt64ColumnOwned)
+	case array.UInt(32):
+		return newUintBuilder(32, nulls, rowsCap, array.NewUInt32ColumnOwned)
+	case array.UInt(16):
+		return newUintBuilder(16, nulls, rowsCap, array.NewUInt16ColumnOwned)
+	case array.UInt(8):
+		return newUintBuilder(8, nulls, rowsCap, array.NewUInt8ColumnOwned)
+	case array.Float(64):
+		return newFloatBuilder(64, nulls, rowsCap, array.NewFloat64ColumnOwned)
+	case array.Float(32):
+		return newFloatBuilder(32, nulls, rowsCap, array.NewFloat32ColumnOwned)
+	default:
+		return newIntBuilder(64, nulls, rowsCap, array.NewInt64ColumnOwned)
+	}
+}
+
+func newIntBuilder[T ~int8 | ~int16 | ~int32 | ~int64](bits int, nulls NullMatcher, rowsCap int, construct func(string, []T, array.Bitmap) array.Column) typedBuilder {
+	return &genericBuilder[T]{
+		data:  make([]T, 0, rowsCap),
+		nulls: nulls,
+		parse: func(raw string) (T, error) {
+			v, err := strconv.ParseInt(raw, 10, bits)
+			return T(v), err
+		},
+		construct: construct,
+	}
+}
+
+func newUintBuilder[T ~uint8 | ~uint16 | ~uint32 | ~uint64](bits int, nulls NullMatcher, rowsCap int, construct func(string, []T, array.Bitmap) array.Column) typedBuilder {
+	return &genericBuilder[T]{
+		data:  make([]T, 0, rowsCap),
+		nulls: nulls,
+		parse: func(raw string) (T, error) {
+			v, err := strconv.ParseUint(raw, 10, bits)
+			return T(v), err
+		},
+		construct: construct,
+	}
+}
+
+func newFloatBuilder[T ~float32 | ~float64](bits int, nulls NullMatcher, rowsCap int, construct func(string, []T, array.Bitmap) array.Column) typedBuilder {
+	return &genericBuilder[T]{
+		data:  make([]T, 0, rowsCap),
+		nulls: nulls,
+		parse: func(raw string) (T, error) {
+			v, err := strconv.ParseFloat(raw, bits)
+			return T(v), err
+		},
+		construct: construct,
+	}
+}
+
+// resolveSpec picks the parse target for a column: an explicit dtype or
+// time format from the plan wins, otherwise the type is inferred from the
+// sample window.
+func resolveSpec(name string, sample []string, nulls NullMatcher, plan ReadPlan) (columnSpec, error) {
+	if dtype, ok := plan.DTypes[name]; ok {
+		if !parsableDType(dtype) {
+			return columnSpec{}, fmt.Errorf("csv cannot parse column %s as %s", name, dtype)
+		}
+		return columnSpec{dtype: dtype}, nil
+	}
+	if layout, ok := plan.DateFormats[name]; ok {
+		return columnSpec{dtype: array.Date(), layout: layout}, nil
+	}
+	if layout, ok := plan.DatetimeFormats[name]; ok {
+		tz := ""
+		if layoutHasZone(layout) {
+			tz = "UTC"
+		}
+		return columnSpec{dtype: array.Datetime(array.TimeUnitUS, tz), layout: layout}, nil
+	}
+	return columnSpec{dtype: inferType(sample, nulls)}, nil
+}
+
+// parsableDType reports whether newBuilder has a parser for dtype.
+func parsableDType(dtype array.DataType) bool {
+	switch dtype.Kind {
+	case array.KindInt, array.KindUInt:
+		return dtype.Bits == 8 || dtype.Bits == 16 || dtype.Bits == 32 || dtype.Bits == 64
+	case array.KindFloat:
+		return dtype.Bits == 32 || dtype.Bits == 64
+	case array.KindBool, array.KindUtf8, array.KindDate:
+		return true
+	case array.KindDatetime:
+		return dtype.Unit != array.TimeUnitInvalid
+	default:
+		return false
+	}
+}
+
+func layoutHasZone(layout string) bool {
+	for _, zone := range []string{"Z07", "-07", "MST"} {
+		if strings.Contains(layout, zone) {
+			return true
+		}
+	}
+	return false
+}
+
+// inferType picks the narrowest type that parses every non-null sample value.
+// Temporal values must be ISO-8601: a column of plain dates infers as date,
+// and datetimes (optionally with fractional seconds) infer as datetime[us].
+// If any value carries a UTC offset the column is tz-aware ("UTC").
 func inferType(values []string, nulls NullMatcher) array.DataType {
 	allInt := true
 	allFloat := true
 	allBool := true
+	allDate := true
+	allDatetime := true
+	anyOffset := false
 	for i := range values {
 		if nulls.IsNull(values[i]) {
 			continue
 		}
-		if _, err := strconv.ParseInt(values[i], 10, 64); err != nil {
-			allInt = false
+		if allInt {
+			if _, err := strconv.ParseInt(values[i], 10, 64); err != nil {
+				allInt = false
+			}
+		}
+		if allFloat {
+			if _, err := strconv.ParseFloat(values[i], 64); err != nil {
+				allFloat = false
+			}
+		}
+		if allBool {
+			if _, err := strconv.ParseBool(strings.ToLower(values[i])); err != nil {
+				allBool = false
+			}
 		}
-		if _, err := strconv.ParseFloat(values[i], 64); err != nil {
-			allFloat = false
+		if allDate {
+			if _, err := array.ParseDate(values[i]); err != nil {
+				allDate = false
+			}
 		}
-		if _, err := strconv.ParseBool(strings.ToLower(values[i])); err != nil {
-			allBool = false
+		if allDatetime {
+			_, offset, err := array.ParseDatetimeOffset(values[i], array.TimeUnitUS, nil)
+			if err != nil {
+				allDatetime = false
+			}
+			anyOffset = anyOffset || offset
 		}
-		if !allInt && !allFloat && !allBool {
+		if !allInt && !allFloat && !allBool && !allDate && !allDatetime {
 			return array.Utf8()
 		}
 	}
@@ -580,6 +963,15 @@ func inferType(values []string, nulls NullMatcher) array.DataType {
 	if allBool {
 		return array.Bool()
 	}
+	if allDate {
+		return array.Date()
+	}
+	if allDatetime {
+		if anyOffset {
+			return array.Datetime(array.TimeUnitUS, "UTC")
+		}
+		return array.Datetime(array.TimeUnitUS, "")
+	}
 	return array.Utf8()
 }
 
@@ -0,0 +1,180 @@
+package csv
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"unicode/utf8"
+
+	"grizzly/internal/array"
+	"grizzly/internal/exec"
+	"grizzly/internal/io/chunked"
+)
+
+// writeChunkRows is the number of rows formatted as one unit of output.
+const writeChunkRows = 16384
+
+type QuoteStyle uint8
+
+const (
+	// QuoteNecessary quotes fields that contain the delimiter, a quote or a
+	// line break, and fields equal to NullValue so they do not read back as
+	// NULL.
+	QuoteNecessary QuoteStyle = iota
+	// QuoteAlways quotes every non-NULL field.
+	QuoteAlways
+	// QuoteNonNumeric quotes every non-NULL field that is not a number.
+	QuoteNonNumeric
+	// QuoteNever writes fields as they are, even when that breaks the format.
+	QuoteNever
+)
+
+type WriteOptions struct {
+	// Delimiter separates fields. Defaults to ','.
+	Delimiter rune
+	Quote     QuoteStyle
+	// NullValue is written for NULLs, unquoted. Defaults to the empty string.
+	NullValue string
+	// NoHeader skips the header row of column names.
+	NoHeader bool
+	// FloatPrecision, when positive, fixes the number of digits after the
+	// decimal point. Otherwise floats use the shortest form that reads back
+	// to the same value.
+	FloatPrecision int
+}
+
+// Write encodes df as CSV. Rows are formatted in parallel chunks, column by
+// column, and written to w in order.
+func Write(w io.Writer, df *exec.DataFrame, opts WriteOptions) error {
+	if opts.Delimiter == 0 {
+		opts.Delimiter = ','
+	}
+	if opts.Delimiter == '"' || opts.Delimiter == '\n' || opts.Delimiter == '\r' || !utf8.ValidRune(opts.Delimiter) {
+		return fmt.Errorf("csv invalid delimiter %q", opts.Delimiter)
+	}
+	if opts.Quote > QuoteNever {
+		return fmt.Errorf("csv invalid quote style")
+	}
+	cols := df.Columns()
+	cw := chunkWriter{cols: cols, opts: opts, delim: utf8.AppendRune(nil, opts.Delimiter)}
+	if !opts.NoHeader {
+		var header []byte
+		for j, c := range cols {
+			if j > 0 {
+				header = append(header, cw.delim...)
+			}
+			header = cw.appendField(header, []byte(c.Name()), false)
+		}
+		if _, err := w.Write(append(header, '\n')); err != nil {
+			return err
+		}
+	}
+
+	return chunked.Write(w, df.Height(), writeChunkRows, true, cw.format)
+}
+
+type chunkWriter struct {
+	cols  []array.Column
+	opts  WriteOptions
+	delim []byte
+}
+
+// format returns rows [start, end) as CSV lines. Each column is formatted on
+// its own first, then the fields are interleaved into lines.
+func (cw chunkWriter) format(start, end int) []byte {
+	fields := make([][]byte, len(cw.cols))
+	ends := make([][]int, len(cw.cols))
+	size := 0
+	for j, c := range cw.cols {
+		fields[j], ends[j] = cw.formatColumn(c, start, end)
+		size += len(fields[j])
+	}
+	out := make([]byte, 0, size+(end-start)*len(cw.cols)*len(cw.delim))
+	pos := make([]int, len(cw.cols))
+	for i := range end - start {
+		for j := range cw.cols {
+			if j > 0 {
+				out = append(out, cw.delim...)
+			}
+			out = append(out, fields[j][pos[j]:ends[j][i]]...)
+			pos[j] = ends[j][i]
+		}
+		out = append(out, '\n')
+	}
+	return out
+}
+
+// formatColumn appends the fields of rows [start, end) of c to one buffer
+// and returns it with the end offset of every field.
+func (cw chunkWriter) formatColumn(c array.Column, start, end int) ([]byte, []int) {
+	var buf, scratch []byte
+	ends := make([]int, 0, end-start)
+	numeric := array.IsNumeric(c.DType())
+	ints, isInt := array.Int64Accessor(c)
+	floats, _ := array.Float64Accessor(c)
+	bits := 64
+	if c.DType() == array.Float(32) {
+		bits = 32
+	}
+	for i := start; i < end; i++ {
+		if c.IsNull(i) {
+			buf = append(buf, cw.opts.NullValue...)
+			ends = append(ends, len(buf))
+			continue
+		}
+		scratch = scratch[:0]
+		switch col := c.(type) {
+		case *array.Utf8Column:
+			s, e := col.ByteRange(i)
+			scratch = col.Bytes()[s:e]
+		case *array.UInt64Column:
+			scratch = strconv.AppendUint(scratch, col.Value(i), 10)
+		case *array.BoolColumn:
+			scratch = strconv.AppendBool(scratch, col.Value(i))
+		default:
+			switch {
+			case isInt:
+				scratch = strconv.AppendInt(scratch, ints(i), 10)
+			case numeric && cw.opts.FloatPrecision > 0:
+				scratch = strconv.AppendFloat(scratch, floats(i), 'f', cw.opts.FloatPrecision, bits)
+			case numeric:
+				scratch = strconv.AppendFloat(scratch, floats(i), 'g', -1, bits)
+			default:
+				scratch = append(scratch, c.ValueString(i)...)
+			}
+		}
+		buf = cw.appendField(buf, scratch, numeric)
+		ends = append(ends, len(buf))
+	}
+	return buf, ends
+}
+
+// appendField appends one non-NULL field, quoted as the options require.
+func (cw chunkWriter) appendField(dst, field []byte, numeric bool) []byte {
+	quote := false
+	switch cw.opts.Quote {
+	case QuoteAlways:
+		quote = true
+	case QuoteNonNumeric:
+		quote = !numeric
+	case QuoteNecessary:
+		quote = bytes.ContainsAny(field, "\"\r\n") || bytes.Contains(field, cw.delim) ||
+			string(field) == cw.opts.NullValue
+	}
+	if !quote {
+		return append(dst, field...)
+	}
+	dst = append(dst, '"')
+	for {
+		k := bytes.IndexByte(field, '"')
+		if k < 0 {
+			break
+		}
+		dst = append(dst, field[:k+1]...)
+		dst = append(dst, '"')
+		field = field[k+1:]
+	}
+	dst = append(dst, field...)
+	return append(dst, '"')
+}
@@ -0,0 +1,408 @@
+package json
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"grizzly/internal/array"
+	"grizzly/internal/exec"
+	"grizzly/internal/expr"
+)
+
+// typeSampleRecords is how many leading records are sampled for field names
+// and types.
+const typeSampleRecords = 8192
+
+// columnBuilder appends scanned values to one typed column.
+type columnBuilder interface {
+	// append adds a non-null value, failing when it does not fit the type.
+	append(v jsonValue) error
+	appendNull()
+	build(name string) array.Column
+}
+
+type valueBuilder[T any] struct {
+	dtype     array.DataType
+	data      []T
+	valid     array.BitmapBuilder
+	parse     func(v jsonValue) (T, bool)
+	construct func(name string, data []T, valid array.Bitmap) array.Column
+}
+
+func (b *valueBuilder[T]) append(v jsonValue) error {
+	x, ok := b.parse(v)
+	if !ok {
+		return fmt.Errorf("%s %s does not fit %s", v.kind, v.text, b.dtype)
+	}
+	b.data = append(b.data, x)
+	b.valid.Append(true)
+	return nil
+}
+
+func (b *valueBuilder[T]) appendNull() {
+	var zero T
+	b.data = append(b.data, zero)
+	b.valid.Append(false)
+}
+
+func (b *valueBuilder[T]) build(name string) array.Column {
+	return b.construct(name, b.data, b.valid.Build())
+}
+
+// utf8Builder takes any value: strings as their contents, everything else
+// as JSON text.
+type utf8Builder struct {
+	offsets []int32
+	bytes   []byte
+	valid   array.BitmapBuilder
+}
+
+func (b *utf8Builder) append(v jsonValue) error {
+	b.bytes = append(b.bytes, v.text...)
+	b.offsets = append(b.offsets, int32(len(b.bytes)))
+	b.valid.Append(true)
+	return nil
+}
+
+func (b *utf8Builder) appendNull() {
+	b.offsets = append(b.offsets, int32(len(b.bytes)))
+	b.valid.Append(false)
+}
+
+func (b *utf8Builder) build(name string) array.Column {
+	return array.NewUtf8ColumnOwned(name, b.offsets, b.bytes, b.valid.Build())
+}
+
+// newColumnBuilder returns a builder for dtype, or nil when JSON values
+// cannot be read as that type.
+func newColumnBuilder(dtype array.DataType, rowsCap int) columnBuilder {
+	rowsCap = max(rowsCap, 16)
+	switch dtype {
+	case array.Int(64):
+		return newIntBuilder(dtype, rowsCap, array.NewInt64ColumnOwned)
+	case array.Int(32):
+		return newIntBuilder(dtype, rowsCap, array.NewInt32ColumnOwned)
+	case array.Int(16):
+		return newIntBuilder(dtype, rowsCap, array.NewInt16ColumnOwned)
+	case array.Int(8):
+		return newIntBuilder(dtype, rowsCap, array.NewInt8ColumnOwned)
+	case array.UInt(64):
+		return newUintBuilder(dtype, rowsCap, array.NewUInt64ColumnOwned)
+	case array.UInt(32):
+		return newUintBuilder(dtype, rowsCap, array.NewUInt32ColumnOwned)
+	case array.UInt(16):
+		return newUintBuilder(dtype, rowsCap, array.NewUInt16ColumnOwned)
+	case array.UInt(8):
+		return newUintBuilder(dtype, rowsCap, array.NewUInt8ColumnOwned)
+	case array.Float(64):
+		return newFloatBuilder(dtype, rowsCap, array.NewFloat64ColumnOwned)
+	case array.Float(32):
+		return newFloatBuilder(dtype, rowsCap, array.NewFloat32ColumnOwned)
+	case array.Bool():
+		return &valueBuilder[bool]{
+			dtype: dtype,
+			data:  make([]bool, 0, rowsCap),
+			parse: func(v jsonValue) (bool, bool) {
+				return v.kind == kindBool && v.text[0] == 't', v.kind == kindBool
+			},
+			construct: array.NewBoolColumnOwned,
+		}
+	case array.Utf8():
+		return &utf8Builder{offsets: make([]int32, 1, rowsCap+1), bytes: make([]byte, 0, rowsCap*8)}
+	default:
+		return nil
+	}
+}
+
+func newIntBuilder[T ~int8 | ~int16 | ~int32 | ~int64](dtype array.DataType, rowsCap int, construct func(string, []T, array.Bitmap) array.Column) columnBuilder {
+	return &valueBuilder[T]{
+		dtype: dtype,
+		data:  make([]T, 0, rowsCap),
+		parse: func(v jsonValue) (T, bool) {
+			if v.kind != kindInt {
+				return 0, false
+			}
+			x, err := strconv.ParseInt(string(v.text), 10, int(dtype.Bits))
+			return T(x), err == nil
+		},
+		construct: construct,
+	}
+}
+
+func newUintBuilder[T ~uint8 | ~uint16 | ~uint32 | ~uint64](dtype array.DataType, rowsCap int, construct func(string, []T, array.Bitmap) array.Column) columnBuilder {
+	return &valueBuilder[T]{
+		dtype: dtype,
+		data:  make([]T, 0, rowsCap),
+		parse: func(v jsonValue) (T, bool) {
+			if v.kind != kindInt {
+				return 0, false
+			}
+			x, err := strconv.ParseUint(string(v.text), 10, int(dtype.Bits))
+			return T(x), err == nil
+		},
+		construct: construct,
+	}
+}
+
+func newFloatBuilder[T ~float32 | ~float64](dtype array.DataType, rowsCap int, construct func(string, []T, array.Bitmap) array.Column) columnBuilder {
+	return &valueBuilder[T]{
+		dtype: dtype,
+		data:  make([]T, 0, rowsCap),
+		parse: func(v jsonValue) (T, bool) {
+			if v.kind != kindInt && v.kind != kindFloat {
+				return 0, false
+			}
+			x, err := strconv.ParseFloat(string(v.text), int(dtype.Bits))
+			return T(x), err == nil
+		},
+		construct: construct,
+	}
+}
+
+// fieldStats records which kinds of value a field held in the sample.
+type fieldStats struct {
+	kinds    uint8
+	negative bool
+	// wide marks an integer above MaxInt64; huge one outside uint64 too.
+	wide bool
+	huge bool
+}
+
+func (st *fieldStats) observe(v jsonValue) {
+	st.kinds |= 1 << v.kind
+	if v.kind != kindInt {
+		return
+	}
+	if v.text[0] == '-' {
+		st.negative = true
+	}
+	if _, err := strconv.ParseInt(string(v.text), 10, 64); err == nil {
+		return
+	}
+	if _, err := strconv.ParseUint(string(v.text), 10, 64); err == nil {
+		st.wide = true
+	} else {
+		st.huge = true
+	}
+}
+
+// dtype picks the narrowest type that holds every sampled value exactly:
+// int64, then uint64, then float64 for integers; text for strings, nested
+// values, mixed kinds and fields that were always null.
+func (st fieldStats) dtype() array.DataType {
+	switch st.kinds &^ (1 << kindNull) {
+	case 1 << kindInt:
+		switch {
+		case st.huge || st.wide && st.negative:
+			return array.Float(64)
+		case st.wide:
+			return array.UInt(64)
+		}
+		return array.Int(64)
+	case 1 << kindFloat, 1<<kindInt | 1<<kindFloat:
+		return array.Float(64)
+	case 1 << kindBool:
+		return array.Bool()
+	default:
+		return array.Utf8()
+	}
+}
+
+// schema is the column layout of a scan, shared by every block of it.
+type schema struct {
+	// label prefixes row errors, e.g. "ndjson line".
+	label string
+	// index maps every known field to its position in names, or -1 when the
+	// scan does not read it.
+	index  map[string]int
+	names  []string
+	dtypes []array.DataType
+	// output marks columns that are returned rather than read only for the
+	// predicate.
+	output []bool
+	pred   expr.Expr
+	// projected is set when a projection limits the columns read. Fields
+	// outside the schema are then skipped, since nothing could read them.
+	projected bool
+}
+
+// sampleRecord is one record of the type-sampling window with its row
+// number for errors.
+type sampleRecord struct {
+	row  int
+	data []byte
+}
+
+// inferSchema resolves the fields seen in records, plus those named in
+// plan.DTypes, into the columns the scan reads.
+func inferSchema(label string, records []sampleRecord, plan ReadPlan) (*schema, error) {
+	stats := make(map[string]*fieldStats, 64)
+	for _, rec := range records {
+		err := decodeRecord(rec.data, func(key []byte, v jsonValue) error {
+			st := stats[string(key)]
+			if st == nil {
+				st = &fieldStats{}
+				stats[string(key)] = st
+			}
+			st.observe(v)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%s %d: %w", label, rec.row, err)
+		}
+	}
+	for k := range plan.DTypes {
+		if stats[k] == nil {
+			stats[k] = &fieldStats{}
+		}
+	}
+	fields := make([]string, 0, len(stats))
+	for k := range stats {
+		fields = append(fields, k)
+	}
+	sort.Strings(fields)
+
+	predCols := map[string]struct{}{}
+	if plan.Predicate != nil {
+		for _, c := range expr.ExprColumns(plan.Predicate) {
+			if _, ok := stats[c]; !ok {
+				return nil, fmt.Errorf("unknown filter column %s", c)
+			}
+			predCols[c] = struct{}{}
+		}
+	}
+
+	s := &schema{label: label, index: make(map[string]int, len(fields)), pred: plan.Predicate, projected: len(plan.Projection) > 0}
+	outputs := 0
+	for _, name := range fields {
+		_, projected := plan.Projection[name]
+		projected = projected || len(plan.Projection) == 0
+		if _, ok := predCols[name]; !ok && !projected {
+			s.index[name] = -1
+			continue
+		}
+		dtype, ok := plan.DTypes[name]
+		if !ok {
+			dtype = stats[name].dtype()
+		} else if newColumnBuilder(dtype, 0) == nil {
+			return nil, fmt.Errorf("cannot read json field %s as %s", name, dtype)
+		}
+		s.index[name] = len(s.names)
+		s.names = append(s.names, name)
+		s.dtypes = append(s.dtypes, dtype)
+		s.output = append(s.output, projected)
+		if projected {
+			outputs++
+		}
+	}
+	if outputs == 0 {
+		return nil, fmt.Errorf("projection selected no columns")
+	}
+	return s, nil
+}
+
+// frameBuilder appends records to one builder per scanned column.
+type frameBuilder struct {
+	s    *schema
+	cols []columnBuilder
+	seen []bool
+	rows int
+	// extend adds fields outside the schema as new columns, NULL in earlier
+	// rows, typed from their first value. It mutates s, so only a builder
+	// that owns its schema may set it. Otherwise such fields fail the read.
+	extend bool
+}
+
+func newFrameBuilder(s *schema, rowsCap int, extend bool) *frameBuilder {
+	fb := &frameBuilder{s: s, cols: make([]columnBuilder, len(s.names)), seen: make([]bool, len(s.names)), extend: extend}
+	for j := range fb.cols {
+		fb.cols[j] = newColumnBuilder(s.dtypes[j], rowsCap)
+	}
+	return fb
+}
+
+func (fb *frameBuilder) add(data []byte, row int) error {
+	clear(fb.seen)
+	err := decodeRecord(data, func(key []byte, v jsonValue) error {
+		j, ok := fb.s.index[string(key)]
+		if !ok {
+			if fb.s.projected {
+				return nil
+			}
+			if !fb.extend {
+				return fmt.Errorf("field %q is not in the schema inferred from the first %d records", key, typeSampleRecords)
+			}
+			j = fb.addColumn(string(key), v)
+		}
+		if j < 0 {
+			return nil
+		}
+		if fb.seen[j] {
+			return fmt.Errorf("duplicate field %q", key)
+		}
+		fb.seen[j] = true
+		if v.kind == kindNull {
+			fb.cols[j].appendNull()
+			return nil
+		}
+		if err := fb.cols[j].append(v); err != nil {
+			return fmt.Errorf("field %q: %w", key, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s %d: %w", fb.s.label, row, err)
+	}
+	for j, ok := range fb.seen {
+		if !ok {
+			fb.cols[j].appendNull()
+		}
+	}
+	fb.rows++
+	return nil
+}
+
+func (fb *frameBuilder) addColumn(name string, first jsonValue) int {
+	var st fieldStats
+	st.observe(first)
+	dtype := st.dtype()
+	b := newColumnBuilder(dtype, fb.rows)
+	for range fb.rows {
+		b.appendNull()
+	}
+	j := len(fb.s.names)
+	fb.s.index[name] = j
+	fb.s.names = append(fb.s.names, name)
+	fb.s.dtypes = append(fb.s.dtypes, dtype)
+	fb.s.output = append(fb.s.output, true)
+	fb.cols = append(fb.cols, b)
+	fb.seen = append(fb.seen, false)
+	return j
+}
+
+// finish builds the columns, applies the predicate and returns the output
+// columns of the rows that pass.
+func (fb *frameBuilder) finish() ([]array.Column, error) {
+	cols := make([]array.Column, len(fb.cols))
+	for j := range fb.cols {
+		cols[j] = fb.cols[j].build(fb.s.names[j])
+	}
+	if fb.s.pred == nil {
+		return cols, nil
+	}
+	df, err := exec.NewDataFrame(cols...)
+	if err != nil {
+		return nil, err
+	}
+	if df, err = df.Filter(fb.s.pred); err != nil {
+		return nil, err
+	}
+	out := make([]array.Column, 0, len(cols))
+	for j, c := range df.Columns() {
+		if fb.s.output[j] {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,271 @@
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// valueKind classifies a scanned JSON value.
+type valueKind uint8
+
+const (
+	kindNull valueKind = iota
+	kindBool
+	// kindInt is a number without a fraction or exponent.
+	kindInt
+	kindFloat
+	kindString
+	// kindNested is an object or array, kept as compact JSON text.
+	kindNested
+)
+
+func (k valueKind) String() string {
+	switch k {
+	case kindNull:
+		return "null"
+	case kindBool:
+		return "boolean"
+	case kindInt:
+		return "integer"
+	case kindFloat:
+		return "number"
+	case kindString:
+		return "string"
+	default:
+		return "nested"
+	}
+}
+
+type jsonValue struct {
+	kind valueKind
+	// text holds a string's unescaped contents, or the JSON text of any
+	// other value, so numbers keep every digit.
+	text []byte
+}
+
+// valueField names the single field of a record that is not an object.
+var valueField = []byte("value")
+
+// decodeRecord scans data, which holds one JSON value, and calls fn for each
+// field: the members of an object, or a single "value" field for anything
+// else. Keys and values may alias data.
+func decodeRecord(data []byte, fn func(key []byte, v jsonValue) error) error {
+	s := scanner{data: data}
+	s.skipSpace()
+	if s.peek() != '{' {
+		v, err := s.value()
+		if err != nil {
+			return err
+		}
+		if err := s.end(); err != nil {
+			return err
+		}
+		return fn(valueField, v)
+	}
+	s.pos++
+	s.skipSpace()
+	if s.peek() == '}' {
+		s.pos++
+		return s.end()
+	}
+	for {
+		s.skipSpace()
+		if s.peek() != '"' {
+			return s.syntaxError("object key")
+		}
+		key, err := s.str()
+		if err != nil {
+			return err
+		}
+		s.skipSpace()
+		if s.peek() != ':' {
+			return s.syntaxError("':'")
+		}
+		s.pos++
+		s.skipSpace()
+		v, err := s.value()
+		if err != nil {
+			return err
+		}
+		if err := fn(key, v); err != nil {
+			return err
+		}
+		s.skipSpace()
+		switch s.peek() {
+		case ',':
+			s.pos++
+		case '}':
+			s.pos++
+			return s.end()
+		default:
+			return s.syntaxError("',' or '}'")
+		}
+	}
+}
+
+// scanner tokenizes one JSON value in place. Strings without escapes,
+// numbers and literals are returned as subslices of the input.
+type scanner struct {
+	data []byte
+	pos  int
+}
+
+func (s *scanner) peek() byte {
+	if s.pos < len(s.data) {
+		return s.data[s.pos]
+	}
+	return 0
+}
+
+func (s *scanner) skipSpace() {
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case ' ', '\t', '\n', '\r':
+			s.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (s *scanner) syntaxError(want string) error {
+	if s.pos >= len(s.data) {
+		return fmt.Errorf("invalid json: expected %s at end of input", want)
+	}
+	return fmt.Errorf("invalid json: expected %s at offset %d", want, s.pos)
+}
+
+// end checks that only whitespace follows the value.
+func (s *scanner) end() error {
+	s.skipSpace()
+	if s.pos != len(s.data) {
+		return s.syntaxError("end of value")
+	}
+	return nil
+}
+
+func (s *scanner) value() (jsonValue, error) {
+	switch c := s.peek(); {
+	case c == '"':
+		text, err := s.str()
+		return jsonValue{kind: kindString, text: text}, err
+	case c == '{' || c == '[':
+		return s.nested()
+	case c == 't':
+		return s.literal("true", kindBool)
+	case c == 'f':
+		return s.literal("false", kindBool)
+	case c == 'n':
+		return s.literal("null", kindNull)
+	case c == '-' || c >= '0' && c <= '9':
+		return s.number()
+	default:
+		return jsonValue{}, s.syntaxError("value")
+	}
+}
+
+func (s *scanner) literal(word string, kind valueKind) (jsonValue, error) {
+	if !bytes.HasPrefix(s.data[s.pos:], []byte(word)) {
+		return jsonValue{}, s.syntaxError(word)
+	}
+	text := s.data[s.pos : s.pos+len(word)]
+	s.pos += len(word)
+	return jsonValue{kind: kind, text: text}, nil
+}
+
+func (s *scanner) number() (jsonValue, error) {
+	start := s.pos
+	kind := kindInt
+	if s.peek() == '-' {
+		s.pos++
+	}
+	if s.peek() == '0' {
+		s.pos++
+	} else if !s.digits() {
+		return jsonValue{}, s.syntaxError("digit")
+	}
+	if s.peek() == '.' {
+		kind = kindFloat
+		s.pos++
+		if !s.digits() {
+			return jsonValue{}, s.syntaxError("digit")
+		}
+	}
+	if c := s.peek(); c == 'e' || c == 'E' {
+		kind = kindFloat
+		s.pos++
+		if c := s.peek(); c == '+' || c == '-' {
+			s.pos++
+		}
+		if !s.digits() {
+			return jsonValue{}, s.syntaxError("digit")
+		}
+	}
+	return jsonValue{kind: kind, text: s.data[start:s.pos]}, nil
+}
+
+func (s *scanner) digits() bool {
+	start := s.pos
+	for s.pos < len(s.data) && s.data[s.pos] >= '0' && s.data[s.pos] <= '9' {
+		s.pos++
+	}
+	return s.pos > start
+}
+
+// str scans a string and returns its contents. Only strings with escapes
+// are copied.
+func (s *scanner) str() ([]byte, error) {
+	start := s.pos + 1
+	escaped := false
+	for i := start; i < len(s.data); i++ {
+		switch c := s.data[i]; {
+		case c == '"':
+			s.pos = i + 1
+			if !escaped {
+				return s.data[start:i], nil
+			}
+			var out string
+			if err := json.Unmarshal(s.data[start-1:i+1], &out); err != nil {
+				return nil, err
+			}
+			return []byte(out), nil
+		case c == '\\':
+			escaped = true
+			i++
+		case c < 0x20:
+			s.pos = i
+			return nil, s.syntaxError("'\"'")
+		}
+	}
+	s.pos = len(s.data)
+	return nil, s.syntaxError("'\"'")
+}
+
+// nested scans an object or array and returns it as compact JSON text.
+func (s *scanner) nested() (jsonValue, error) {
+	start := s.pos
+	depth := 0
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case '"':
+			if _, err := s.str(); err != nil {
+				return jsonValue{}, err
+			}
+			continue
+		case '{', '[':
+			depth++
+		case '}', ']':
+			depth--
+		}
+		s.pos++
+		if depth == 0 {
+			var buf bytes.Buffer
+			if err := json.Compact(&buf, s.data[start:s.pos]); err != nil {
+				return jsonValue{}, err
+			}
+			return jsonValue{kind: kindNested, text: buf.Bytes()}, nil
+		}
+	}
+	return jsonValue{}, s.syntaxError("end of nested value")
+}
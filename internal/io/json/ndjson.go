@@ -0,0 +1,250 @@
+package json
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sync"
+
+	"grizzly/internal/array"
+	"grizzly/internal/exec"
+	"grizzly/internal/expr"
+)
+
+// ndjsonBlockBytes is the target size of one parse unit. Blocks end on a
+// line break, so no record spans two of them.
+const ndjsonBlockBytes = 1 << 20
+
+type ReadPlan struct {
+	Projection map[string]struct{}
+	// Predicate is evaluated on every parsed block; only matching rows are
+	// kept, so it must be row-local (see expr.IsRowLocal). Columns it reads
+	// need not be in Projection.
+	Predicate expr.Expr
+	// MaxRows, when positive, lets the reader stop once that many rows have
+	// passed the predicate. It may still return more rows than that.
+	MaxRows int
+	// DTypes forces the named fields to a type instead of inferring it.
+	// Named fields are columns even when the sampled records lack them.
+	DTypes map[string]array.DataType
+}
+
+// ReadNDJSON reads newline-delimited JSON, one record per line. Fields and
+// their types come from the first records; a later record with a field
+// outside that schema fails the read, unless a projection leaves it unread.
+// Blank lines are skipped.
+func ReadNDJSON(ctx context.Context, path string, plan ReadPlan) (*exec.DataFrame, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	cancellable := ctx.Done() != nil
+	if cancellable {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	br := &blockReader{r: bufio.NewReaderSize(f, 1<<16), line: 1}
+	var pending []ndjsonBlock
+	var sample []sampleRecord
+	for len(sample) < typeSampleRecords {
+		block, ok, err := br.next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		pending = append(pending, block)
+		_ = forEachLine(block, func(line int, text []byte) error {
+			if len(sample) < typeSampleRecords {
+				sample = append(sample, sampleRecord{row: line, data: text})
+			}
+			return nil
+		})
+	}
+	if len(sample) == 0 && len(plan.DTypes) == 0 {
+		return nil, fmt.Errorf("ndjson has no records")
+	}
+	s, err := inferSchema("ndjson line", sample, plan)
+	if err != nil {
+		return nil, err
+	}
+
+	workers := max(runtime.GOMAXPROCS(0), 1)
+	var parts [][]array.Column
+	kept := 0
+	for {
+		for len(pending) < workers {
+			block, ok, err := br.next()
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				break
+			}
+			pending = append(pending, block)
+		}
+		if len(pending) == 0 {
+			break
+		}
+		if cancellable {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		results, err := parseBatch(s, pending)
+		if err != nil {
+			return nil, err
+		}
+		for _, cols := range results {
+			parts = append(parts, cols)
+			kept += cols[0].Len()
+		}
+		pending = pending[:0]
+		if plan.MaxRows > 0 && kept >= plan.MaxRows {
+			break
+		}
+	}
+	if len(parts) == 0 {
+		// An empty file with declared DTypes still has a schema.
+		cols, err := parseBlock(s, ndjsonBlock{})
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, cols)
+	}
+	return concatParts(parts)
+}
+
+// ndjsonBlock is a run of whole lines; line is the number of its first.
+type ndjsonBlock struct {
+	data []byte
+	line int
+}
+
+type blockReader struct {
+	r    io.Reader
+	rest []byte
+	line int
+	eof  bool
+}
+
+// next returns the next block of at least ndjsonBlockBytes, unless the input
+// ends first, and false once the input is exhausted.
+func (br *blockReader) next() (ndjsonBlock, bool, error) {
+	if br.eof {
+		return ndjsonBlock{}, false, nil
+	}
+	buf := make([]byte, max(ndjsonBlockBytes, 2*len(br.rest)))
+	n := copy(buf, br.rest)
+	for {
+		m, err := io.ReadFull(br.r, buf[n:])
+		n += m
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			br.eof = true
+			if n == 0 {
+				return ndjsonBlock{}, false, nil
+			}
+			return br.emit(buf[:n]), true, nil
+		}
+		if err != nil {
+			return ndjsonBlock{}, false, err
+		}
+		if k := bytes.LastIndexByte(buf[:n], '\n'); k >= 0 {
+			br.rest = buf[k+1 : n]
+			return br.emit(buf[:k+1]), true, nil
+		}
+		// A single line fills the buffer; grow it and keep reading.
+		buf = append(buf, make([]byte, len(buf))...)
+	}
+}
+
+func (br *blockReader) emit(data []byte) ndjsonBlock {
+	block := ndjsonBlock{data: data, line: br.line}
+	br.line += bytes.Count(data, []byte{'\n'})
+	return block
+}
+
+// forEachLine calls fn for every non-blank line of block.
+func forEachLine(block ndjsonBlock, fn func(line int, text []byte) error) error {
+	data := block.data
+	for line := block.line; len(data) > 0; line++ {
+		text := data
+		if k := bytes.IndexByte(data, '\n'); k >= 0 {
+			text, data = data[:k], data[k+1:]
+		} else {
+			data = nil
+		}
+		text = bytes.TrimSpace(text)
+		if len(text) == 0 {
+			continue
+		}
+		if err := fn(line, text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseBatch parses every block in its own goroutine and returns their
+// output columns in block order.
+func parseBatch(s *schema, batch []ndjsonBlock) ([][]array.Column, error) {
+	results := make([][]array.Column, len(batch))
+	errs := make([]error, len(batch))
+	var wg sync.WaitGroup
+	for i := range batch {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = parseBlock(s, batch[i])
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// parseBlock appends the records of one block to typed builders, applies the
+// predicate and returns the output columns of the rows that pass.
+func parseBlock(s *schema, block ndjsonBlock) ([]array.Column, error) {
+	fb := newFrameBuilder(s, bytes.Count(block.data, []byte{'\n'})+1, false)
+	if err := forEachLine(block, func(line int, text []byte) error {
+		return fb.add(text, line)
+	}); err != nil {
+		return nil, err
+	}
+	return fb.finish()
+}
+
+func concatParts(parts [][]array.Column) (*exec.DataFrame, error) {
+	if len(parts) == 1 {
+		return exec.NewDataFrame(parts[0]...)
+	}
+	cols := make([]array.Column, len(parts[0]))
+	chunks := make([]array.Column, len(parts))
+	for j := range cols {
+		for p := range parts {
+			chunks[p] = parts[p][j]
+		}
+		col, err := array.Concat(chunks...)
+		if err != nil {
+			return nil, err
+		}
+		cols[j] = col
+	}
+	return exec.NewDataFrame(cols...)
+}
@@ -2,6 +2,7 @@ package json
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -12,10 +13,29 @@ import (
 
 	"grizzly/internal/array"
 	"grizzly/internal/exec"
-	csvio "grizzly/internal/io/csv"
 )
 
-func Read(ctx context.Context, path string) (*exec.DataFrame, error) {
+type ReadOptions struct {
+	// MaxRows, when positive, stops reading after that many records; the
+	// rest of the input is never decoded.
+	MaxRows int
+	// RecordPath selects the records inside the document, as a JSON pointer
+	// ("/data/items") or a dotted path ("data.items"). Numeric segments index
+	// arrays. Empty selects the root.
+	RecordPath string
+	// KeyColumn, when set, expects the selected value to be an object of
+	// records and turns each member into a row, with the member's key in a
+	// column of this name.
+	KeyColumn string
+	// DTypes forces the named fields to a type instead of inferring it.
+	DTypes map[string]array.DataType
+}
+
+// Read reads the records at opts.RecordPath: the elements of an array, or an
+// object as a single row unless opts.KeyColumn explodes it. With no
+// RecordPath, a root object with a "tasks" array reads as that array.
+// Records are streamed, so a limit or cancellation stops reading early.
+func Read(ctx context.Context, path string, opts ReadOptions) (*exec.DataFrame, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
@@ -32,296 +52,298 @@ func Read(ctx context.Context, path string) (*exec.DataFrame, error) {
 	}
 	defer f.Close()
 
-	br := bufio.NewReader(f)
-	first, err := peekFirstNonSpaceByte(br)
+	dec := json.NewDecoder(bufio.NewReader(f))
+	if err := seekRecordPath(dec, opts.RecordPath); err != nil {
+		return nil, err
+	}
+	tok, err := dec.Token()
 	if err != nil {
 		return nil, err
 	}
-
-	dec := json.NewDecoder(br)
-	if first == '[' {
-		// Stream large top-level arrays so cancellation can interrupt scans.
-		tok, err := dec.Token()
+	var next func() (json.RawMessage, bool, error)
+	switch {
+	case tok == json.Delim('['):
+		next = streamValues(dec, func() (json.RawMessage, error) {
+			var raw json.RawMessage
+			err := dec.Decode(&raw)
+			return raw, err
+		})
+	case tok == json.Delim('{') && opts.KeyColumn != "":
+		next = streamValues(dec, func() (json.RawMessage, error) {
+			name, raw, err := nextMember(dec)
+			if err != nil {
+				return nil, err
+			}
+			return keyedRecord(opts.KeyColumn, name, raw)
+		})
+	case tok == json.Delim('{'):
+		// A lone object is one row; rebuild it from its members.
+		obj, err := readObjectMembers(dec)
 		if err != nil {
 			return nil, err
 		}
-		d, ok := tok.(json.Delim)
-		if !ok || d != '[' {
-			return nil, fmt.Errorf("expected json array")
-		}
-		rows := make([]map[string]any, 0, 1024)
-		const ctxCheckMask = 1024 - 1
-		iter := 0
-		for dec.More() {
-			iter++
-			if cancellable && (iter&ctxCheckMask) == 0 {
-				if err := ctx.Err(); err != nil {
-					return nil, err
+		if tasks, ok := defaultTasks(obj); ok && opts.RecordPath == "" {
+			next = func() (json.RawMessage, bool, error) {
+				if len(tasks) == 0 {
+					return nil, false, nil
 				}
+				raw := tasks[0]
+				tasks = tasks[1:]
+				return raw, true, nil
 			}
-			var elem any
-			if err := dec.Decode(&elem); err != nil {
-				return nil, err
-			}
-			obj, ok := elem.(map[string]any)
-			if !ok {
-				obj = map[string]any{"value": elem}
-			}
-			rows = append(rows, obj)
+			break
 		}
-		if _, err := dec.Token(); err != nil {
-			return nil, err
+		next = func() (json.RawMessage, bool, error) {
+			raw := obj
+			obj = nil
+			return raw, raw != nil, nil
 		}
-		return recordsToFrame(ctx, rows)
+	case opts.RecordPath != "":
+		return nil, fmt.Errorf("json record path %s selects a scalar, not records", opts.RecordPath)
+	default:
+		return nil, fmt.Errorf("unsupported json root type")
 	}
+	return readRecords(ctx, next, opts)
+}
 
-	// For object roots, decode as a whole (common for nested exports).
-	var payload any
-	if err := dec.Decode(&payload); err != nil {
-		return nil, err
+// defaultTasks returns the elements of obj's "tasks" member when it is an
+// array. Without a RecordPath, such a root object is read as its tasks, as it
+// was before record paths existed.
+func defaultTasks(obj json.RawMessage) ([]json.RawMessage, bool) {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(obj, &members); err != nil {
+		return nil, false
 	}
-	rows, err := normalizeJSONRows(ctx, payload)
-	if err != nil {
-		return nil, err
+	var tasks []json.RawMessage
+	if raw, ok := members["tasks"]; !ok || json.Unmarshal(raw, &tasks) != nil || tasks == nil {
+		return nil, false
 	}
-	return recordsToFrame(ctx, rows)
+	return tasks, true
 }
 
-func peekFirstNonSpaceByte(br *bufio.Reader) (byte, error) {
-	for {
-		b, err := br.ReadByte()
-		if err != nil {
-			return 0, err
-		}
-		if b == ' ' || b == '\n' || b == '\r' || b == '\t' {
-			continue
-		}
-		if err := br.UnreadByte(); err != nil {
-			return 0, err
+// parseRecordPath splits a JSON pointer or dotted path into its segments.
+func parseRecordPath(path string) []string {
+	if path == "" {
+		return nil
+	}
+	if rest, ok := strings.CutPrefix(path, "/"); ok {
+		segs := strings.Split(rest, "/")
+		unescape := strings.NewReplacer("~1", "/", "~0", "~")
+		for i := range segs {
+			segs[i] = unescape.Replace(segs[i])
 		}
-		return b, nil
+		return segs
 	}
+	return strings.Split(path, ".")
 }
 
-func normalizeJSONRows(ctx context.Context, v any) ([]map[string]any, error) {
-	cancellable := ctx != nil && ctx.Done() != nil
-	const ctxCheckMask = 1024 - 1
-	switch x := v.(type) {
-	case []any:
-		rows := make([]map[string]any, 0, len(x))
-		for i := range x {
-			if cancellable && (i&ctxCheckMask) == 0 {
-				if err := ctx.Err(); err != nil {
-					return nil, err
-				}
-			}
-			obj, ok := x[i].(map[string]any)
-			if !ok {
-				obj = map[string]any{"value": x[i]}
-			}
-			rows = append(rows, obj)
+// seekRecordPath advances dec to the start of the value at path, skipping
+// every sibling before it without keeping it.
+func seekRecordPath(dec *json.Decoder, path string) error {
+	for _, seg := range parseRecordPath(path) {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
 		}
-		return rows, nil
-	case map[string]any:
-		if tasks, ok := x["tasks"].([]any); ok {
-			rows := make([]map[string]any, 0, len(tasks))
-			for i := range tasks {
-				if cancellable && (i&ctxCheckMask) == 0 {
-					if err := ctx.Err(); err != nil {
-						return nil, err
-					}
+		switch tok {
+		case json.Delim('{'):
+			found := false
+			for !found && dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return err
 				}
-				obj, ok := tasks[i].(map[string]any)
-				if !ok {
-					obj = map[string]any{"value": tasks[i]}
+				if found = keyTok.(string) == seg; !found {
+					if err := skipValue(dec); err != nil {
+						return err
+					}
 				}
-				rows = append(rows, obj)
-			}
-			return rows, nil
-		}
-		return []map[string]any{x}, nil
-	default:
-		return nil, fmt.Errorf("unsupported json root type")
-	}
-}
-
-func recordsToFrame(ctx context.Context, rows []map[string]any) (*exec.DataFrame, error) {
-	cancellable := ctx != nil && ctx.Done() != nil
-	const ctxCheckMask = 1024 - 1
-	if len(rows) == 0 {
-		return nil, fmt.Errorf("json rows empty")
-	}
-	keySet := make(map[string]struct{}, 64)
-	for i := range rows {
-		if cancellable && (i&ctxCheckMask) == 0 {
-			if err := ctx.Err(); err != nil {
-				return nil, err
 			}
-		}
-		for k := range rows[i] {
-			keySet[k] = struct{}{}
-		}
-	}
-	keys := make([]string, 0, len(keySet))
-	for k := range keySet {
-		keys = append(keys, k)
-	}
-	sort.Strings(keys)
-	cols := make([]array.Column, 0, len(keys))
-	nulls := csvio.NewNullMatcher([]string{""})
-	for _, key := range keys {
-		if cancellable {
-			if err := ctx.Err(); err != nil {
-				return nil, err
+			if !found {
+				return fmt.Errorf("json record path %s: no key %q", path, seg)
 			}
-		}
-		raw := make([]string, 0, len(rows))
-		for i := range rows {
-			v, ok := rows[i][key]
-			if !ok || v == nil {
-				raw = append(raw, "")
-				continue
+		case json.Delim('['):
+			idx, err := strconv.Atoi(seg)
+			if err != nil || idx < 0 {
+				return fmt.Errorf("json record path %s: %q does not index an array", path, seg)
 			}
-			raw = append(raw, fmt.Sprint(v))
-		}
-		dtype := inferType(raw, nulls)
-		b := newBuilder(dtype, nulls, len(raw))
-		for i := range raw {
-			if cancellable && (i&ctxCheckMask) == 0 {
-				if err := ctx.Err(); err != nil {
-					return nil, err
+			for ; idx > 0 && dec.More(); idx-- {
+				if err := skipValue(dec); err != nil {
+					return err
 				}
 			}
-			if err := b.Append(raw[i], i+1); err != nil {
-				return nil, err
+			if !dec.More() {
+				return fmt.Errorf("json record path %s: index %s out of range", path, seg)
 			}
+		default:
+			return fmt.Errorf("json record path %s: %q is below a scalar", path, seg)
 		}
-		cols = append(cols, b.Build(key))
 	}
-	return exec.NewDataFrame(cols...)
+	return nil
 }
 
-// minimal builder logic for JSON read path (shares NullMatcher with CSV IO).
-type typedBuilder interface {
-	Append(raw string, row int) error
-	Build(name string) array.Column
+// streamValues returns an iterator over the values of the array or object
+// whose opening token dec consumed. The closing token is read too, so
+// truncated input fails instead of reading as fewer records.
+func streamValues(dec *json.Decoder, decode func() (json.RawMessage, error)) func() (json.RawMessage, bool, error) {
+	done := false
+	return func() (json.RawMessage, bool, error) {
+		if done {
+			return nil, false, nil
+		}
+		if !dec.More() {
+			done = true
+			_, err := dec.Token()
+			return nil, false, err
+		}
+		raw, err := decode()
+		return raw, err == nil, err
+	}
 }
 
-type genericBuilder[T any] struct {
-	data      []T
-	valid     array.BitmapBuilder
-	nulls     csvio.NullMatcher
-	parse     func(string) (T, error)
-	construct func(name string, data []T, valid array.Bitmap) array.Column
+func skipValue(dec *json.Decoder) error {
+	var skip json.RawMessage
+	return dec.Decode(&skip)
 }
 
-func (b *genericBuilder[T]) Append(raw string, row int) error {
-	var zero T
-	b.data = append(b.data, zero)
-	if b.nulls.IsNull(raw) {
-		b.valid.Append(false)
-		return nil
-	}
-	v, err := b.parse(raw)
+// nextMember reads one key and value of an object whose '{' was consumed.
+func nextMember(dec *json.Decoder) (string, json.RawMessage, error) {
+	tok, err := dec.Token()
 	if err != nil {
-		return fmt.Errorf("row %d parse value: %w", row, err)
+		return "", nil, err
 	}
-	b.data[len(b.data)-1] = v
-	b.valid.Append(true)
-	return nil
-}
-func (b *genericBuilder[T]) Build(name string) array.Column {
-	return b.construct(name, b.data, b.valid.Build())
-}
-
-type utf8Builder struct {
-	offsets []int32
-	bytes   []byte
-	valid   array.BitmapBuilder
-	nulls   csvio.NullMatcher
+	var raw json.RawMessage
+	if err := dec.Decode(&raw); err != nil {
+		return "", nil, err
+	}
+	return tok.(string), raw, nil
 }
 
-func (b *utf8Builder) Append(raw string, _ int) error {
-	if b.nulls.IsNull(raw) {
-		b.valid.Append(false)
-		b.offsets = append(b.offsets, int32(len(b.bytes)))
-		return nil
+// readObjectMembers reads the rest of an object whose '{' was consumed and
+// returns it re-encoded as one JSON object.
+func readObjectMembers(dec *json.Decoder) (json.RawMessage, error) {
+	out := []byte{'{'}
+	for dec.More() {
+		name, raw, err := nextMember(dec)
+		if err != nil {
+			return nil, err
+		}
+		if len(out) > 1 {
+			out = append(out, ',')
+		}
+		out = appendJSONString(out, name)
+		out = append(out, ':')
+		out = append(out, raw...)
 	}
-	b.valid.Append(true)
-	b.bytes = append(b.bytes, raw...)
-	b.offsets = append(b.offsets, int32(len(b.bytes)))
-	return nil
-}
-func (b *utf8Builder) Build(name string) array.Column {
-	return array.NewUtf8ColumnOwned(name, b.offsets, b.bytes, b.valid.Build())
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	return append(out, '}'), nil
 }
 
-func newBuilder(dtype array.DataType, nulls csvio.NullMatcher, rowsCap int) typedBuilder {
-	if rowsCap < 16 {
-		rowsCap = 16
-	}
-	switch dtype.Kind {
-	case array.KindInt:
-		return &genericBuilder[int64]{
-			data:  make([]int64, 0, rowsCap),
-			nulls: nulls,
-			parse: func(raw string) (int64, error) { return strconv.ParseInt(raw, 10, 64) },
-			construct: func(name string, data []int64, valid array.Bitmap) array.Column {
-				return array.NewInt64ColumnOwned(name, data, valid)
-			},
-		}
-	case array.KindFloat:
-		return &genericBuilder[float64]{
-			data:  make([]float64, 0, rowsCap),
-			nulls: nulls,
-			parse: func(raw string) (float64, error) { return strconv.ParseFloat(raw, 64) },
-			construct: func(name string, data []float64, valid array.Bitmap) array.Column {
-				return array.NewFloat64ColumnOwned(name, data, valid)
-			},
+// keyedRecord returns the record of one exploded member: its object with the
+// key column prepended, or the key column and a "value" field otherwise. An
+// object that already has a field named keyColumn is an error.
+func keyedRecord(keyColumn, name string, raw json.RawMessage) (json.RawMessage, error) {
+	out := make([]byte, 0, len(keyColumn)+len(name)+len(raw)+16)
+	out = append(out, '{')
+	out = appendJSONString(out, keyColumn)
+	out = append(out, ':')
+	out = appendJSONString(out, name)
+	if len(raw) > 0 && raw[0] == '{' {
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		if _, err := dec.Token(); err != nil {
+			return nil, err
 		}
-	case array.KindBool:
-		return &genericBuilder[bool]{
-			data:  make([]bool, 0, rowsCap),
-			nulls: nulls,
-			parse: func(raw string) (bool, error) { return strconv.ParseBool(strings.ToLower(raw)) },
-			construct: func(name string, data []bool, valid array.Bitmap) array.Column {
-				return array.NewBoolColumnOwned(name, data, valid)
-			},
+		for dec.More() {
+			field, value, err := nextMember(dec)
+			if err != nil {
+				return nil, err
+			}
+			if field == keyColumn {
+				return nil, fmt.Errorf("member %q has a field %q, which collides with the key column", name, keyColumn)
+			}
+			out = append(out, ',')
+			out = appendJSONString(out, field)
+			out = append(out, ':')
+			out = append(out, value...)
 		}
-	default:
-		return &utf8Builder{offsets: make([]int32, 1, rowsCap+1), bytes: make([]byte, 0, rowsCap*8), nulls: nulls}
+		return append(out, '}'), nil
 	}
+	out = append(out, `,"value":`...)
+	out = append(out, raw...)
+	return append(out, '}'), nil
 }
 
-func inferType(values []string, nulls csvio.NullMatcher) array.DataType {
-	allInt := true
-	allFloat := true
-	allBool := true
-	for i := range values {
-		if nulls.IsNull(values[i]) {
-			continue
-		}
-		if _, err := strconv.ParseInt(values[i], 10, 64); err != nil {
-			allInt = false
+// readRecords infers the schema from the first records, then appends every
+// record straight into typed builders. Fields first seen after the sample
+// window become new columns, NULL in earlier rows. A positive maxRows stops
+// reading after that many records.
+func readRecords(ctx context.Context, next func() (json.RawMessage, bool, error), opts ReadOptions) (*exec.DataFrame, error) {
+	maxRows := opts.MaxRows
+	cancellable := ctx.Done() != nil
+	const ctxCheckMask = 1024 - 1
+	rows := 0
+	read := func() (json.RawMessage, bool, error) {
+		if maxRows > 0 && rows >= maxRows {
+			// Stop early; the rest of the input is never decoded.
+			return nil, false, nil
 		}
-		if _, err := strconv.ParseFloat(values[i], 64); err != nil {
-			allFloat = false
+		rows++
+		if cancellable && (rows&ctxCheckMask) == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, false, err
+			}
 		}
-		if _, err := strconv.ParseBool(strings.ToLower(values[i])); err != nil {
-			allBool = false
+		return next()
+	}
+
+	sample := make([]sampleRecord, 0, 1024)
+	for len(sample) < typeSampleRecords {
+		raw, ok, err := read()
+		if err != nil {
+			return nil, err
 		}
-		if !allInt && !allFloat && !allBool {
-			return array.Utf8()
+		if !ok {
+			break
 		}
+		sample = append(sample, sampleRecord{row: len(sample) + 1, data: raw})
+	}
+	if len(sample) == 0 {
+		return nil, fmt.Errorf("json rows empty")
+	}
+	s, err := inferSchema("json record", sample, ReadPlan{DTypes: opts.DTypes})
+	if err != nil {
+		return nil, err
 	}
-	if allInt {
-		return array.Int(64)
+	fb := newFrameBuilder(s, len(sample), true)
+	for _, rec := range sample {
+		if err := fb.add(rec.data, rec.row); err != nil {
+			return nil, err
+		}
 	}
-	if allFloat {
-		return array.Float(64)
+	for {
+		raw, ok, err := read()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		if err := fb.add(raw, fb.rows+1); err != nil {
+			return nil, err
+		}
 	}
-	if allBool {
-		return array.Bool()
+	cols, err := fb.finish()
+	if err != nil {
+		return nil, err
 	}
-	return array.Utf8()
+	// Late fields were appended; keep columns in key order.
+	sort.Slice(cols, func(i, j int) bool { return cols[i].Name() < cols[j].Name() })
+	return exec.NewDataFrame(cols...)
+}
+
+func appendJSONString(dst []byte, s string) []byte {
+	b, _ := json.Marshal(s)
+	return append(dst, b...)
 }
@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 
+	"grizzly/internal/array"
 	"grizzly/internal/exec"
 	"grizzly/internal/expr"
 	csvio "grizzly/internal/io/csv"
@@ -15,6 +17,31 @@ import (
 type ScanOptions struct {
 	Delimiter  rune
 	NullValues []string
+	// DateFormats and DatetimeFormats force the named columns to date or
+	// datetime and parse them with a Go time layout (e.g. "02/01/2006")
+	// instead of inferring the type. Datetime layouts that contain a zone
+	// produce tz-aware ("UTC") columns.
+	DateFormats     map[string]string
+	DatetimeFormats map[string]string
+	// DTypes forces the named columns to a type instead of inferring it, for
+	// example array.Int(32) or array.UInt(8) for compact IDs and readings.
+	DTypes map[string]array.DataType
+}
+
+// JSONScanOptions configure ScanJSON and ScanNDJSON.
+type JSONScanOptions struct {
+	// DTypes forces the named fields to a type instead of inferring it from
+	// the first records. Named fields become columns even when those records
+	// lack them.
+	DTypes map[string]array.DataType
+	// RecordPath selects the records of a JSON document, as a JSON pointer
+	// ("/data/items") or a dotted path ("data.items"). Empty selects the
+	// root.
+	RecordPath string
+	// KeyColumn explodes an object of records into rows, one per member,
+	// with the member's key in a column of this name. A member object with
+	// its own field of that name fails the read.
+	KeyColumn string
 }
 
 type sourceKind uint8
@@ -22,12 +49,14 @@ type sourceKind uint8
 const (
 	sourceCSV sourceKind = iota + 1
 	sourceJSON
+	sourceNDJSON
 )
 
 type lazySource struct {
 	kind sourceKind
 	path string
 	csv  ScanOptions
+	json JSONScanOptions
 }
 
 type opType uint8
@@ -36,14 +65,70 @@ const (
 	opSelect opType = iota + 1
 	opFilter
 	opSort
+	opGroupBy
+	opJoin
+	opWithColumns
+	opSlice
+	// opTopK is a Sort followed by a Slice from offset 0, fused by the
+	// optimizer; length holds k.
+	opTopK
 )
 
 type op struct {
 	typeID opType
 	cols   []string
 	filter expr.Expr
-	sortBy string
-	desc   bool
+	sortBy []exec.SortKey
+	stable bool
+	keys   []string
+	aggs   []exec.AggSpec
+	right  *LazyFrame
+	join   exec.JoinOptions
+	vals   []expr.Value
+	offset int
+	length int
+}
+
+// inputColumns returns the columns an op reads from its input. Only Select
+// and GroupBy narrow the schema; for those the result bounds the scan.
+func (o op) inputColumns() []string {
+	switch o.typeID {
+	case opSelect:
+		return o.cols
+	case opGroupBy:
+		cols := append([]string(nil), o.keys...)
+		for _, a := range o.aggs {
+			if a.Col != "" {
+				cols = append(cols, a.Col)
+			}
+		}
+		return cols
+	case opSort, opTopK:
+		cols := make([]string, len(o.sortBy))
+		for i, k := range o.sortBy {
+			cols[i] = k.Col
+		}
+		return cols
+	case opFilter:
+		return expr.ExprColumns(o.filter)
+	case opJoin:
+		leftOn, _ := joinKeys(o.join)
+		return leftOn
+	case opWithColumns:
+		var cols []string
+		seen := map[string]struct{}{}
+		for _, v := range o.vals {
+			for _, c := range expr.ValueColumns(v) {
+				if _, ok := seen[c]; !ok {
+					seen[c] = struct{}{}
+					cols = append(cols, c)
+				}
+			}
+		}
+		return cols
+	default:
+		return nil
+	}
 }
 
 type LazyFrame struct {
@@ -51,80 +136,163 @@ type LazyFrame struct {
 	ops    []op
 }
 
-// Explain returns a human-readable plan.
-// It includes any scan pushdowns that will apply during Collect.
+// Explain returns a human-readable plan tree, one node per line with
+// children indented below their parent. Scan nodes include any pushdowns
+// that will apply during Collect. A join's right input is rendered directly
+// under the join, before the rest of the left chain, and both sit one level
+// below the join.
 func (lf *LazyFrame) Explain() (string, error) {
-	optimized := lf.optimize()
-
 	var b strings.Builder
-	switch optimized.source.kind {
-	case sourceCSV:
-		b.WriteString("Source: csv\n")
-		b.WriteString("Path: ")
-		b.WriteString(optimized.source.path)
+	opt, err := lf.optimize()
+	if err == nil {
+		err = opt.explainInto(&b, 0)
+	}
+	if err != nil {
+		b.WriteString("planning error: ")
+		b.WriteString(err.Error())
 		b.WriteByte('\n')
-		if optimized.source.csv.Delimiter != 0 {
-			b.WriteString("Delimiter: ")
-			b.WriteRune(optimized.source.csv.Delimiter)
-			b.WriteByte('\n')
+		return b.String(), err
+	}
+	return b.String(), nil
+}
+
+func (lf *LazyFrame) explainInto(b *strings.Builder, depth int) error {
+	var scan string
+	ops := lf.ops
+	switch lf.source.kind {
+	case sourceCSV:
+		readPlan, remaining, err := lf.scanReadPlan(true)
+		if err != nil {
+			return err
 		}
-		if len(optimized.source.csv.NullValues) > 0 {
-			b.WriteString("NullValues: ")
-			b.WriteString(strings.Join(optimized.source.csv.NullValues, ","))
-			b.WriteByte('\n')
+		scan = formatCSVScan(lf.source, readPlan)
+		ops = remaining
+	case sourceJSON:
+		var b strings.Builder
+		b.WriteString("scan json path=")
+		b.WriteString(lf.source.path)
+		if lf.source.json.RecordPath != "" {
+			b.WriteString(" record_path=" + lf.source.json.RecordPath)
 		}
-
-		readPlan, remainingOps, err := optimized.csvReadPlan()
+		if lf.source.json.KeyColumn != "" {
+			b.WriteString(" key_column=" + lf.source.json.KeyColumn)
+		}
+		writeDTypes(&b, lf.source.json.DTypes)
+		scan = b.String()
+	case sourceNDJSON:
+		readPlan, remaining, err := lf.scanReadPlan(false)
 		if err != nil {
-			b.WriteString("PlanningError: ")
-			b.WriteString(err.Error())
-			b.WriteByte('\n')
-			return b.String(), err
+			return err
 		}
+		var b strings.Builder
+		b.WriteString("scan ndjson path=")
+		b.WriteString(lf.source.path)
+		writeDTypes(&b, lf.source.json.DTypes)
+		writePushdowns(&b, readPlan)
+		scan = b.String()
+		ops = remaining
+	default:
+		return fmt.Errorf("unknown source kind")
+	}
 
-		b.WriteString("Scan: ")
-		if len(readPlan.Projection) > 0 {
-			b.WriteString("projection=[")
-			keys := make([]string, 0, len(readPlan.Projection))
-			for k := range readPlan.Projection {
-				keys = append(keys, k)
+	// Ops render from the last (the root) down to the scan, one indent per
+	// level. A join's right input renders directly under the join, ahead of
+	// the rest of the chain, so each subtree sits under its own parent.
+	for i := len(ops) - 1; i >= 0; i-- {
+		writeExplainLine(b, depth, formatOp(ops[i]))
+		depth++
+		if ops[i].typeID == opJoin {
+			if err := ops[i].right.explainInto(b, depth); err != nil {
+				return err
 			}
-			sort.Strings(keys)
-			b.WriteString(strings.Join(keys, ","))
-			b.WriteByte(']')
-		} else {
-			b.WriteString("projection=* (all)")
 		}
-		if readPlan.FilterEven != "" {
-			b.WriteString(" filter_even=")
-			b.WriteString(readPlan.FilterEven)
-		}
-		b.WriteByte('\n')
+	}
+	writeExplainLine(b, depth, scan)
+	return nil
+}
 
-		b.WriteString("Ops:\n")
-		for _, op := range remainingOps {
-			b.WriteString("- ")
-			b.WriteString(formatOp(op))
-			b.WriteByte('\n')
-		}
-		return b.String(), nil
+func writeExplainLine(b *strings.Builder, depth int, line string) {
+	b.WriteString(strings.Repeat("  ", depth))
+	b.WriteString(line)
+	b.WriteByte('\n')
+}
 
-	case sourceJSON:
-		b.WriteString("Source: json\n")
-		b.WriteString("Path: ")
-		b.WriteString(optimized.source.path)
-		b.WriteByte('\n')
-		b.WriteString("Ops:\n")
-		for _, op := range optimized.ops {
-			b.WriteString("- ")
-			b.WriteString(formatOp(op))
-			b.WriteByte('\n')
+func formatCSVScan(src lazySource, readPlan csvio.ReadPlan) string {
+	var b strings.Builder
+	b.WriteString("scan csv path=")
+	b.WriteString(src.path)
+	if src.csv.Delimiter != 0 {
+		b.WriteString(" delimiter=")
+		b.WriteRune(src.csv.Delimiter)
+	}
+	if len(src.csv.NullValues) > 0 {
+		b.WriteString(" null_values=[")
+		b.WriteString(strings.Join(src.csv.NullValues, ","))
+		b.WriteByte(']')
+	}
+	writeFormats(&b, " date_formats=", src.csv.DateFormats)
+	writeFormats(&b, " datetime_formats=", src.csv.DatetimeFormats)
+	writeDTypes(&b, src.csv.DTypes)
+	writePushdowns(&b, readPlan)
+	return b.String()
+}
+
+func writeDTypes(b *strings.Builder, dtypes map[string]array.DataType) {
+	if len(dtypes) == 0 {
+		return
+	}
+	names := make(map[string]string, len(dtypes))
+	for name, dt := range dtypes {
+		names[name] = dt.String()
+	}
+	writeFormats(b, " dtypes=", names)
+}
+
+// writePushdowns renders the projection and filters a scan applies itself.
+func writePushdowns(b *strings.Builder, readPlan csvio.ReadPlan) {
+	if len(readPlan.Projection) > 0 {
+		keys := make([]string, 0, len(readPlan.Projection))
+		for k := range readPlan.Projection {
+			keys = append(keys, k)
 		}
-		return b.String(), nil
+		sort.Strings(keys)
+		b.WriteString(" projection=[")
+		b.WriteString(strings.Join(keys, ","))
+		b.WriteByte(']')
+	} else {
+		b.WriteString(" projection=* (all)")
+	}
+	if readPlan.FilterEven != "" {
+		b.WriteString(" filter_even=")
+		b.WriteString(readPlan.FilterEven)
+	}
+	if readPlan.Predicate != nil {
+		b.WriteString(" predicate(cols=[")
+		b.WriteString(strings.Join(expr.ExprColumns(readPlan.Predicate), ","))
+		b.WriteString("])")
+	}
+}
 
-	default:
-		return "", fmt.Errorf("unknown source kind")
+func writeFormats(b *strings.Builder, label string, formats map[string]string) {
+	if len(formats) == 0 {
+		return
 	}
+	keys := make([]string, 0, len(formats))
+	for k := range formats {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	b.WriteString(label)
+	b.WriteByte('[')
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(formats[k])
+	}
+	b.WriteByte(']')
 }
 
 func formatOp(op op) string {
@@ -132,20 +300,60 @@ func formatOp(op op) string {
 	case opSelect:
 		return "select(" + strings.Join(op.cols, ",") + ")"
 	case opSort:
-		dir := "asc"
-		if op.desc {
-			dir = "desc"
-		}
-		return "sort(" + op.sortBy + "," + dir + ")"
+		return "sort(" + formatSortKeys(op.sortBy) + ")"
 	case opFilter:
-		cols := expr.ExprColumns(op.filter)
-		sort.Strings(cols)
-		return "filter(cols=[" + strings.Join(cols, ",") + "])"
+		// One column list per conjunct, in evaluation order.
+		parts := expr.Conjuncts(op.filter)
+		lists := make([]string, len(parts))
+		for i, part := range parts {
+			cols := expr.ExprColumns(part)
+			sort.Strings(cols)
+			lists[i] = "[" + strings.Join(cols, ",") + "]"
+		}
+		return "filter(cols=" + strings.Join(lists, " & ") + ")"
+	case opGroupBy:
+		aggs := make([]string, len(op.aggs))
+		for i, a := range op.aggs {
+			aggs[i], _ = exec.AggOutputName(a)
+		}
+		return "groupby(keys=[" + strings.Join(op.keys, ",") + "],aggs=[" + strings.Join(aggs, ",") + "])"
+	case opJoin:
+		leftOn, rightOn := joinKeys(op.join)
+		if len(op.join.On) > 0 {
+			return "join(" + op.join.How.String() + ",on=[" + strings.Join(leftOn, ",") + "])"
+		}
+		return "join(" + op.join.How.String() + ",left_on=[" + strings.Join(leftOn, ",") + "],right_on=[" + strings.Join(rightOn, ",") + "])"
+	case opWithColumns:
+		names := make([]string, len(op.vals))
+		for i, v := range op.vals {
+			names[i] = expr.OutputName(v)
+		}
+		return "with_columns(" + strings.Join(names, ",") + ")"
+	case opSlice:
+		return "slice(offset=" + strconv.Itoa(op.offset) + ",length=" + strconv.Itoa(op.length) + ")"
+	case opTopK:
+		return "top_k(" + formatSortKeys(op.sortBy) + ",k=" + strconv.Itoa(op.length) + ")"
 	default:
 		return "op(?)"
 	}
 }
 
+// formatSortKeys renders keys as col,dir pairs separated by ';', flagging
+// nulls_first where set.
+func formatSortKeys(keys []exec.SortKey) string {
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k.Col + ",asc"
+		if k.Desc {
+			parts[i] = k.Col + ",desc"
+		}
+		if k.NullsFirst {
+			parts[i] += ",nulls_first"
+		}
+	}
+	return strings.Join(parts, ";")
+}
+
 func ScanCSV(path string, opts ScanOptions) *LazyFrame {
 	if len(opts.NullValues) == 0 {
 		opts.NullValues = []string{"", "NULL", "null"}
@@ -153,8 +361,15 @@ func ScanCSV(path string, opts ScanOptions) *LazyFrame {
 	return &LazyFrame{source: lazySource{kind: sourceCSV, path: path, csv: opts}}
 }
 
-func ScanJSON(path string) *LazyFrame {
-	return &LazyFrame{source: lazySource{kind: sourceJSON, path: path}}
+// ScanJSON scans a JSON document with the given options.
+func ScanJSON(path string, opts JSONScanOptions) *LazyFrame {
+	return &LazyFrame{source: lazySource{kind: sourceJSON, path: path, json: opts}}
+}
+
+// ScanNDJSON scans newline-delimited JSON, one object per line. Projections,
+// filters and leading limits push into the scan as they do for CSV.
+func ScanNDJSON(path string, opts JSONScanOptions) *LazyFrame {
+	return &LazyFrame{source: lazySource{kind: sourceNDJSON, path: path, json: opts}}
 }
 
 func (lf *LazyFrame) Select(cols ...string) *LazyFrame {
@@ -170,8 +385,58 @@ func (lf *LazyFrame) Filter(e expr.Expr) *LazyFrame {
 }
 
 func (lf *LazyFrame) Sort(col string, desc bool) *LazyFrame {
+	return lf.SortKeys([]exec.SortKey{{Col: col, Desc: desc}}, exec.SortOptions{MaintainOrder: true})
+}
+
+// SortKeys sorts by several keys, each with its own direction and NULL
+// placement; see exec.DataFrame.SortKeys.
+func (lf *LazyFrame) SortKeys(keys []exec.SortKey, opts exec.SortOptions) *LazyFrame {
+	next := *lf
+	sortBy := append([]exec.SortKey(nil), keys...)
+	next.ops = append(append([]op(nil), lf.ops...), op{typeID: opSort, sortBy: sortBy, stable: opts.MaintainOrder})
+	return &next
+}
+
+// Slice keeps length rows starting at offset. Without a sort, GroupBy or
+// join before it, scans stop reading once enough rows passed the filters; a
+// Sort directly before it becomes a top-k.
+func (lf *LazyFrame) Slice(offset, length int) *LazyFrame {
+	next := *lf
+	next.ops = append(append([]op(nil), lf.ops...), op{typeID: opSlice, offset: offset, length: length})
+	return &next
+}
+
+func (lf *LazyFrame) Head(n int) *LazyFrame  { return lf.Slice(0, n) }
+func (lf *LazyFrame) Limit(n int) *LazyFrame { return lf.Head(n) }
+
+// WithColumns adds or replaces columns computed from value expressions.
+func (lf *LazyFrame) WithColumns(vals ...expr.Value) *LazyFrame {
+	next := *lf
+	next.ops = append(append([]op(nil), lf.ops...), op{typeID: opWithColumns, vals: append([]expr.Value(nil), vals...)})
+	return &next
+}
+
+// LazyGroupBy is a pending GroupBy; Agg turns it into a plan node.
+type LazyGroupBy struct {
+	lf   *LazyFrame
+	keys []string
+}
+
+func (lf *LazyFrame) GroupBy(keys ...string) *LazyGroupBy {
+	return &LazyGroupBy{lf: lf, keys: append([]string(nil), keys...)}
+}
+
+func (g *LazyGroupBy) Agg(specs ...exec.AggSpec) *LazyFrame {
+	next := *g.lf
+	next.ops = append(append([]op(nil), g.lf.ops...), op{typeID: opGroupBy, keys: g.keys, aggs: append([]exec.AggSpec(nil), specs...)})
+	return &next
+}
+
+// Join adds a join with another plan. Each side keeps its own scan and
+// pushdowns; see exec.DataFrame.Join for semantics.
+func (lf *LazyFrame) Join(other *LazyFrame, opts exec.JoinOptions) *LazyFrame {
 	next := *lf
-	next.ops = append(append([]op(nil), lf.ops...), op{typeID: opSort, sortBy: col, desc: desc})
+	next.ops = append(append([]op(nil), lf.ops...), op{typeID: opJoin, right: other, join: opts})
 	return &next
 }
 
@@ -183,91 +448,181 @@ func (lf *LazyFrame) CollectContext(ctx context.Context) (*exec.DataFrame, error
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	optimized := lf.optimize()
+	opt, err := lf.optimize()
+	if err != nil {
+		return nil, err
+	}
+	return opt.execute(ctx)
+}
+
+// execute runs an already optimized plan.
+func (lf *LazyFrame) execute(ctx context.Context) (*exec.DataFrame, error) {
 	var df *exec.DataFrame
 	var err error
-	if optimized.source.kind == sourceCSV {
-		readPlan, remainingOps, err := optimized.csvReadPlan()
+	ops := lf.ops
+	switch lf.source.kind {
+	case sourceCSV:
+		readPlan, remainingOps, err := lf.scanReadPlan(true)
 		if err != nil {
 			return nil, err
 		}
-		df, err = csvio.Read(ctx, optimized.source.path, optimized.source.csv.Delimiter, optimized.source.csv.NullValues, readPlan)
+		df, err = csvio.Read(ctx, lf.source.path, lf.source.csv.Delimiter, lf.source.csv.NullValues, readPlan)
 		if err != nil {
 			return nil, err
 		}
-		for _, op := range remainingOps {
-			switch op.typeID {
-			case opFilter:
-				df, err = df.Filter(op.filter)
-			case opSelect:
-				df, err = df.Select(op.cols...)
-			case opSort:
-				df, err = df.SortBy(op.sortBy, op.desc)
-			}
-			if err != nil {
-				return nil, err
-			}
-		}
-		return df, nil
-	}
-
-	switch optimized.source.kind {
+		ops = remainingOps
 	case sourceJSON:
-		df, err = jsonio.Read(ctx, optimized.source.path)
+		df, err = jsonio.Read(ctx, lf.source.path, jsonio.ReadOptions{
+			MaxRows:    leadingLimit(lf.ops),
+			RecordPath: lf.source.json.RecordPath,
+			KeyColumn:  lf.source.json.KeyColumn,
+			DTypes:     lf.source.json.DTypes,
+		})
+		if err != nil {
+			return nil, err
+		}
+	case sourceNDJSON:
+		readPlan, remainingOps, err := lf.scanReadPlan(false)
+		if err != nil {
+			return nil, err
+		}
+		df, err = jsonio.ReadNDJSON(ctx, lf.source.path, jsonio.ReadPlan{
+			Projection: readPlan.Projection,
+			Predicate:  readPlan.Predicate,
+			MaxRows:    readPlan.MaxRows,
+			DTypes:     lf.source.json.DTypes,
+		})
+		if err != nil {
+			return nil, err
+		}
+		ops = remainingOps
 	default:
 		return nil, fmt.Errorf("unknown source kind")
 	}
-	if err != nil {
-		return nil, err
+	for _, op := range ops {
+		df, err = applyOp(ctx, df, op)
+		if err != nil {
+			return nil, err
+		}
 	}
-	for _, op := range optimized.ops {
-		switch op.typeID {
-		case opFilter:
-			df, err = df.Filter(op.filter)
-		case opSelect:
-			df, err = df.Select(op.cols...)
-		case opSort:
-			df, err = df.SortBy(op.sortBy, op.desc)
+	return df, nil
+}
+
+func applyOp(ctx context.Context, df *exec.DataFrame, op op) (*exec.DataFrame, error) {
+	switch op.typeID {
+	case opFilter:
+		return df.Filter(op.filter)
+	case opSelect:
+		return df.Select(op.cols...)
+	case opSort:
+		return df.SortKeys(op.sortBy, exec.SortOptions{MaintainOrder: op.stable})
+	case opGroupBy:
+		gb, err := df.GroupBy(op.keys...)
+		if err != nil {
+			return nil, err
 		}
+		return gb.Agg(op.aggs...)
+	case opJoin:
+		right, err := op.right.execute(ctx)
 		if err != nil {
 			return nil, err
 		}
+		return df.Join(right, op.join)
+	case opWithColumns:
+		return df.WithColumnValues(op.vals...)
+	case opSlice:
+		return df.Slice(op.offset, op.length)
+	case opTopK:
+		return df.TopKBy(op.length, op.sortBy)
+	default:
+		return nil, fmt.Errorf("unknown op")
 	}
-	return df, nil
 }
 
-func (lf *LazyFrame) optimize() *LazyFrame {
-	// Optimizations must preserve sequential semantics.
-	// For now, we do not reorder operations (reordering across Select can change
-	// error behavior when a filter references a dropped column).
-	next := *lf
-	next.ops = append([]op(nil), lf.ops...)
-	return &next
+// leadingLimit returns how many source rows suffice when ops only project
+// or compute row-local columns before a Slice, or 0 when every row may be
+// needed.
+func leadingLimit(ops []op) int {
+	for _, o := range ops {
+		switch o.typeID {
+		case opSelect:
+		case opWithColumns:
+			for _, v := range o.vals {
+				if !expr.IsRowLocalValue(v) {
+					return 0
+				}
+			}
+		case opSlice:
+			if o.offset < 0 || o.length < 0 {
+				return 0
+			}
+			return o.offset + o.length
+		default:
+			return 0
+		}
+	}
+	return 0
 }
 
-func (lf *LazyFrame) csvReadPlan() (csvio.ReadPlan, []op, error) {
-	plan := csvio.ReadPlan{}
+// scanReadPlan splits the ops into the pushdowns a scan applies and the ops
+// left to run on its output. With rawEven, one Even conjunct is tested on
+// raw CSV fields before parsing; otherwise it stays in the predicate.
+func (lf *LazyFrame) scanReadPlan(rawEven bool) (csvio.ReadPlan, []op, error) {
+	plan := csvio.ReadPlan{
+		DateFormats:     lf.source.csv.DateFormats,
+		DatetimeFormats: lf.source.csv.DatetimeFormats,
+		DTypes:          lf.source.csv.DTypes,
+	}
 	remaining := make([]op, 0, len(lf.ops))
 
 	var visible map[string]struct{}
 
-	firstSelectIdx := -1
+	// The first Select or GroupBy bounds which columns the scan must produce:
+	// its own inputs plus whatever earlier ops read. Ops past a join also see
+	// the right side's columns, so only a narrowing op before it counts.
+	narrowIdx := -1
 	for i := range lf.ops {
-		if lf.ops[i].typeID == opSelect {
-			firstSelectIdx = i
+		if lf.ops[i].typeID == opJoin {
 			break
 		}
-	}
-
-	scanNeeded := map[string]struct{}{}
-	if firstSelectIdx >= 0 {
-		for _, c := range lf.ops[firstSelectIdx].cols {
-			scanNeeded[c] = struct{}{}
+		if lf.ops[i].typeID == opSelect || lf.ops[i].typeID == opGroupBy {
+			narrowIdx = i
+			break
 		}
 	}
 
+	scanNeeded := map[string]struct{}{}
+	// computed holds names produced by WithColumns; the scan never needs them
+	// and filters on them cannot move into it.
+	computed := map[string]struct{}{}
+
+	// Filters after a GroupBy, join or slice see different rows than the
+	// scan produces and cannot move into it. Neither can anything after an
+	// op that is not row-local, which must see every row the scan produces.
+	barrier := false
+	// streaming holds while every row the scan keeps reaches the next op
+	// in file order and nothing so far reads other rows, so a Slice can
+	// bound how much the scan reads.
+	streaming := true
 	for i := range lf.ops {
 		op := lf.ops[i]
+		// Row-local filters on scanned columns before any GroupBy or join
+		// run inside the scan, on one chunk at a time; the reader loads
+		// their columns itself.
+		pushed := false
+		if op.typeID == opFilter && !barrier && expr.IsRowLocal(op.filter) {
+			pushed = allIn(expr.ExprColumns(op.filter), func(c string) bool {
+				_, isComputed := computed[c]
+				return !isComputed
+			})
+		}
+		if i <= narrowIdx && !pushed {
+			for _, c := range op.inputColumns() {
+				if _, ok := computed[c]; !ok {
+					scanNeeded[c] = struct{}{}
+				}
+			}
+		}
 		switch op.typeID {
 		case opSelect:
 			if visible != nil {
@@ -283,17 +638,63 @@ func (lf *LazyFrame) csvReadPlan() (csvio.ReadPlan, []op, error) {
 			}
 			remaining = append(remaining, op)
 
-		case opSort:
+		case opGroupBy:
+			if visible != nil {
+				for _, c := range op.inputColumns() {
+					if _, ok := visible[c]; !ok {
+						return csvio.ReadPlan{}, nil, fmt.Errorf("groupby unknown column %s", c)
+					}
+				}
+			}
+			visible = make(map[string]struct{}, len(op.keys)+len(op.aggs))
+			for _, k := range op.keys {
+				visible[k] = struct{}{}
+			}
+			for _, a := range op.aggs {
+				name, err := exec.AggOutputName(a)
+				if err != nil {
+					return csvio.ReadPlan{}, nil, err
+				}
+				visible[name] = struct{}{}
+			}
+			barrier = true
+			streaming = false
+			remaining = append(remaining, op)
+
+		case opJoin:
 			if visible != nil {
-				if _, ok := visible[op.sortBy]; !ok {
-					return csvio.ReadPlan{}, nil, fmt.Errorf("sort unknown column %s", op.sortBy)
+				for _, c := range op.inputColumns() {
+					if _, ok := visible[c]; !ok {
+						return csvio.ReadPlan{}, nil, fmt.Errorf("join unknown column %s", c)
+					}
 				}
 			}
-			if firstSelectIdx >= 0 && i < firstSelectIdx {
-				scanNeeded[op.sortBy] = struct{}{}
+			// The output schema depends on the right input; stop validating.
+			visible = nil
+			barrier = true
+			streaming = false
+			remaining = append(remaining, op)
+
+		case opSort, opTopK:
+			for _, k := range op.sortBy {
+				if _, ok := visible[k.Col]; visible != nil && !ok {
+					return csvio.ReadPlan{}, nil, fmt.Errorf("sort unknown column %s", k.Col)
+				}
+			}
+			streaming = false
+			if op.typeID == opTopK {
+				barrier = true
 			}
 			remaining = append(remaining, op)
 
+		case opSlice:
+			if streaming && op.offset >= 0 && op.length >= 0 {
+				plan.MaxRows = op.offset + op.length
+			}
+			barrier = true
+			streaming = false
+			remaining = append(remaining, op)
+
 		case opFilter:
 			cols := expr.ExprColumns(op.filter)
 			if visible != nil {
@@ -303,24 +704,57 @@ func (lf *LazyFrame) csvReadPlan() (csvio.ReadPlan, []op, error) {
 					}
 				}
 			}
-			if col, ok := expr.IsEven(op.filter); ok && plan.FilterEven == "" {
-				plan.FilterEven = col
-				if firstSelectIdx >= 0 && i < firstSelectIdx {
-					scanNeeded[col] = struct{}{}
+			if !pushed {
+				if !expr.IsRowLocal(op.filter) {
+					barrier = true
 				}
+				streaming = false
+				remaining = append(remaining, op)
 				continue
 			}
-			// fallback: only collect scan-needed columns for pre-select filters
-			if firstSelectIdx >= 0 && i < firstSelectIdx {
-				for _, c := range cols {
-					scanNeeded[c] = struct{}{}
+			// Even runs on raw fields before parsing; anything else is
+			// evaluated per parsed chunk.
+			var parts []expr.Expr
+			for _, part := range expr.Conjuncts(op.filter) {
+				if col, ok := expr.IsEven(part); ok && rawEven && plan.FilterEven == "" {
+					plan.FilterEven = col
+					continue
+				}
+				parts = append(parts, part)
+			}
+			if len(parts) == 0 {
+				continue
+			}
+			if plan.Predicate != nil {
+				parts = append(expr.Conjuncts(plan.Predicate), parts...)
+			}
+			plan.Predicate = expr.AndAll(parts)
+
+		case opWithColumns:
+			for _, c := range op.inputColumns() {
+				if visible == nil {
+					break
+				}
+				if _, ok := visible[c]; !ok {
+					return csvio.ReadPlan{}, nil, fmt.Errorf("with_columns unknown column %s", c)
+				}
+			}
+			for _, v := range op.vals {
+				if !expr.IsRowLocalValue(v) {
+					barrier = true
+					streaming = false
+				}
+				name := expr.OutputName(v)
+				computed[name] = struct{}{}
+				if visible != nil {
+					visible[name] = struct{}{}
 				}
 			}
 			remaining = append(remaining, op)
 		}
 	}
 
-	if firstSelectIdx >= 0 {
+	if narrowIdx >= 0 {
 		if plan.FilterEven != "" {
 			scanNeeded[plan.FilterEven] = struct{}{}
 		}
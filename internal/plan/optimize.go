@@ -0,0 +1,445 @@
+package plan
+
+import (
+	"fmt"
+	"sort"
+
+	"grizzly/internal/exec"
+	"grizzly/internal/expr"
+	csvio "grizzly/internal/io/csv"
+)
+
+// optimize validates lf and returns a rewritten copy. Every column reference
+// is checked against the known schemas first, so the rewrites below cannot
+// change which planning error a plan reports.
+//
+// Rules, applied to each chain:
+//   - filters move ahead of sorts, so fewer rows are sorted;
+//   - filters directly after a join move into the input they concern, and
+//     projections reach both scans;
+//   - consecutive filters merge into one conjunction whose conjuncts are
+//     ordered by estimated cost and selectivity (see expr.Cost and
+//     expr.Selectivity); exec evaluates them in that order.
+//
+// Only row-local filters (see expr.IsRowLocal) are split, moved or merged;
+// any other filter stays whole where it was written.
+func (lf *LazyFrame) optimize() (*LazyFrame, error) {
+	if err := lf.validate(); err != nil {
+		return nil, err
+	}
+	return lf.rewrite(), nil
+}
+
+func (lf *LazyFrame) rewrite() *LazyFrame {
+	next := *lf
+	next.ops = hoistFilters(splitFilters(lf.ops))
+	// Later joins first: what they push left lands after earlier joins,
+	// which can then push it further down.
+	for i := len(next.ops) - 1; i >= 0; i-- {
+		if next.ops[i].typeID == opJoin {
+			next.pushThroughJoin(i)
+		}
+	}
+	for i := range next.ops {
+		if next.ops[i].typeID == opJoin {
+			next.ops[i].right = next.ops[i].right.rewrite()
+		}
+	}
+	next.ops = fuseTopK(mergeFilters(next.ops))
+	return &next
+}
+
+// validate reports the first op, in plan order, that references a column
+// its input does not have. Ops whose input schema is unknown until data is
+// read (JSON sources, or after joins with such inputs) are checked at run
+// time instead.
+func (lf *LazyFrame) validate() error {
+	cols, known := lf.schemaAt(0)
+	for _, o := range lf.ops {
+		if o.typeID == opJoin {
+			if err := o.right.validate(); err != nil {
+				return err
+			}
+			if right, ok := o.right.schemaAt(len(o.right.ops)); ok {
+				_, rightOn := joinKeys(o.join)
+				for _, c := range rightOn {
+					if !contains(right, c) {
+						return fmt.Errorf("join unknown right column %s", c)
+					}
+				}
+			}
+		}
+		if known {
+			for _, c := range o.inputColumns() {
+				if !contains(cols, c) {
+					return fmt.Errorf("%s unknown column %s", opName(o.typeID), c)
+				}
+			}
+		}
+		cols, known = o.outputSchema(cols, known)
+	}
+	return nil
+}
+
+func opName(t opType) string {
+	switch t {
+	case opSelect:
+		return "select"
+	case opFilter:
+		return "filter"
+	case opSort:
+		return "sort"
+	case opGroupBy:
+		return "groupby"
+	case opJoin:
+		return "join"
+	case opWithColumns:
+		return "with_columns"
+	case opSlice:
+		return "slice"
+	case opTopK:
+		return "top_k"
+	default:
+		return "op"
+	}
+}
+
+// splitFilters replaces every row-local filter by one filter per conjunct,
+// so each can move on its own.
+func splitFilters(ops []op) []op {
+	out := make([]op, 0, len(ops))
+	for _, o := range ops {
+		if o.typeID != opFilter || !expr.IsRowLocal(o.filter) {
+			out = append(out, o)
+			continue
+		}
+		for _, part := range expr.Conjuncts(o.filter) {
+			out = append(out, op{typeID: opFilter, filter: part})
+		}
+	}
+	return out
+}
+
+// hoistFilters moves each row-local filter ahead of the sorts directly
+// before it.
+func hoistFilters(ops []op) []op {
+	out := append([]op(nil), ops...)
+	for k := range out {
+		if out[k].typeID != opFilter || !expr.IsRowLocal(out[k].filter) {
+			continue
+		}
+		for j := k; j > 0 && out[j-1].typeID == opSort; j-- {
+			out[j-1], out[j] = out[j], out[j-1]
+		}
+	}
+	return out
+}
+
+// mergeFilters combines runs of consecutive row-local filters into one
+// conjunction, cheapest and most selective conjuncts first.
+func mergeFilters(ops []op) []op {
+	rowLocal := func(o op) bool { return o.typeID == opFilter && expr.IsRowLocal(o.filter) }
+	out := make([]op, 0, len(ops))
+	for k := 0; k < len(ops); {
+		if !rowLocal(ops[k]) {
+			out = append(out, ops[k])
+			k++
+			continue
+		}
+		var parts []expr.Expr
+		for ; k < len(ops) && rowLocal(ops[k]); k++ {
+			parts = append(parts, expr.Conjuncts(ops[k].filter)...)
+		}
+		out = append(out, op{typeID: opFilter, filter: expr.AndAll(orderConjuncts(parts))})
+	}
+	return out
+}
+
+// fuseTopK turns a Sort directly followed by a Slice into a top-k of
+// offset+length rows, keeping the Slice only when it skips rows.
+func fuseTopK(ops []op) []op {
+	out := make([]op, 0, len(ops))
+	for k := 0; k < len(ops); k++ {
+		o := ops[k]
+		if o.typeID == opSort && k+1 < len(ops) && ops[k+1].typeID == opSlice {
+			sl := ops[k+1]
+			if sl.offset >= 0 && sl.length >= 0 {
+				out = append(out, op{typeID: opTopK, sortBy: o.sortBy, length: sl.offset + sl.length})
+				if sl.offset > 0 {
+					out = append(out, sl)
+				}
+				k++
+				continue
+			}
+		}
+		out = append(out, o)
+	}
+	return out
+}
+
+// orderConjuncts sorts predicates by cost per row eliminated, the classic
+// rank for ordering independent filters. Ties keep the written order.
+func orderConjuncts(parts []expr.Expr) []expr.Expr {
+	rank := func(e expr.Expr) float64 {
+		return expr.Cost(e) / max(1-expr.Selectivity(e), 0.01)
+	}
+	out := append([]expr.Expr(nil), parts...)
+	sort.SliceStable(out, func(i, j int) bool { return rank(out[i]) < rank(out[j]) })
+	return out
+}
+
+// pushThroughJoin moves work that follows the join at index j into its
+// inputs. Both input schemas must be known at plan time; otherwise the join
+// is left untouched.
+func (lf *LazyFrame) pushThroughJoin(j int) {
+	jop := lf.ops[j]
+	leftCols, ok := lf.schemaAt(j)
+	if !ok {
+		return
+	}
+	rightCols, ok := jop.right.schemaAt(len(jop.right.ops))
+	if !ok {
+		return
+	}
+	how := jop.join.How
+	leftOn, rightOn := joinKeys(jop.join)
+	leftSet := stringSet(leftCols)
+	rightKeys := stringSet(rightOn)
+	suffix := jop.join.Suffix
+	if suffix == "" {
+		suffix = "_right"
+	}
+
+	// Filters directly after the join (sorts in between commute with them).
+	// A filter on left columns can run before the join unless the join may
+	// NULL-fill or coalesce left rows; likewise for unsuffixed right columns.
+	// A filter that is not row-local ends the scan: it must see every joined
+	// row, and nothing after it may move past it.
+	right := jop.right
+	var pushedLeft []op
+	k := j + 1
+	for k < len(lf.ops) {
+		o := lf.ops[k]
+		if o.typeID == opSort {
+			k++
+			continue
+		}
+		if o.typeID != opFilter || !expr.IsRowLocal(o.filter) {
+			break
+		}
+		cols := expr.ExprColumns(o.filter)
+		toLeft := how != exec.JoinRight && how != exec.JoinFull && allIn(cols, func(c string) bool {
+			_, ok := leftSet[c]
+			return ok
+		})
+		toRight := (how == exec.JoinInner || how == exec.JoinRight) && allIn(cols, func(c string) bool {
+			_, isLeft := leftSet[c]
+			_, isKey := rightKeys[c]
+			return !isLeft && !isKey && contains(rightCols, c)
+		})
+		switch {
+		case toLeft:
+			pushedLeft = append(pushedLeft, o)
+		case toRight:
+			right = right.Filter(o.filter)
+		default:
+			k++
+			continue
+		}
+		lf.ops = append(lf.ops[:k], lf.ops[k+1:]...)
+	}
+
+	// Projection: the first Select or GroupBy after the join (past filters
+	// and sorts) bounds the columns each input must produce.
+	var needed map[string]struct{}
+	if isNarrowed(lf.ops[j+1:]) {
+		needed = map[string]struct{}{}
+		for _, o := range lf.ops[j+1:] {
+			for _, c := range o.inputColumns() {
+				needed[c] = struct{}{}
+			}
+			if o.typeID == opSelect || o.typeID == opGroupBy {
+				break
+			}
+		}
+	}
+
+	var leftNeed, rightNeed []string
+	switch {
+	case how == exec.JoinSemi || how == exec.JoinAnti:
+		rightNeed = rightOn
+	case needed != nil:
+		keep := stringSet(leftOn)
+		for c := range needed {
+			keep[c] = struct{}{}
+		}
+		for _, c := range rightCols {
+			_, isKey := rightKeys[c]
+			out := c
+			if _, clash := leftSet[c]; clash {
+				out = c + suffix
+			}
+			if _, ok := needed[out]; ok || isKey {
+				rightNeed = append(rightNeed, c)
+				// Keep the clashing left column so the suffix still applies.
+				if out != c {
+					keep[c] = struct{}{}
+				}
+			}
+		}
+		for _, c := range leftCols {
+			if _, ok := keep[c]; ok {
+				leftNeed = append(leftNeed, c)
+			}
+		}
+	}
+
+	if len(rightNeed) > 0 && len(rightNeed) < len(rightCols) {
+		right = right.Select(rightNeed...)
+	}
+	lf.ops[j].right = right
+	if len(leftNeed) > 0 && len(leftNeed) < len(leftCols) {
+		pushedLeft = append(pushedLeft, op{typeID: opSelect, cols: leftNeed})
+	}
+	if len(pushedLeft) > 0 {
+		ops := make([]op, 0, len(lf.ops)+len(pushedLeft))
+		ops = append(ops, lf.ops[:j]...)
+		ops = append(ops, pushedLeft...)
+		ops = append(ops, lf.ops[j:]...)
+		lf.ops = ops
+	}
+}
+
+// isNarrowed reports whether ops reach a Select or GroupBy through filters,
+// sorts and computed columns only.
+func isNarrowed(ops []op) bool {
+	for _, o := range ops {
+		switch o.typeID {
+		case opSelect, opGroupBy:
+			return true
+		case opFilter, opSort, opWithColumns, opSlice, opTopK:
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// schemaAt returns the column names produced after the first n ops, or false
+// when they cannot be known without reading data. CSV sources only need
+// their header.
+func (lf *LazyFrame) schemaAt(n int) ([]string, bool) {
+	var cols []string
+	known := false
+	if lf.source.kind == sourceCSV {
+		header, err := csvio.ReadHeader(lf.source.path, lf.source.csv.Delimiter)
+		cols, known = header, err == nil
+	}
+	for _, o := range lf.ops[:n] {
+		cols, known = o.outputSchema(cols, known)
+	}
+	return cols, known
+}
+
+// outputSchema returns the columns o produces from input columns cols.
+// Select and GroupBy fix their output even when the input is unknown.
+func (o op) outputSchema(cols []string, known bool) ([]string, bool) {
+	switch o.typeID {
+	case opSelect:
+		return o.cols, true
+	case opGroupBy:
+		next := append([]string(nil), o.keys...)
+		for _, a := range o.aggs {
+			name, err := exec.AggOutputName(a)
+			if err != nil {
+				return nil, false
+			}
+			next = append(next, name)
+		}
+		return next, true
+	case opJoin:
+		if !known {
+			return nil, false
+		}
+		right, ok := o.right.schemaAt(len(o.right.ops))
+		if !ok {
+			return nil, false
+		}
+		return joinOutputColumns(cols, right, o.join), true
+	case opWithColumns:
+		if !known {
+			return nil, false
+		}
+		next := append([]string(nil), cols...)
+		for _, v := range o.vals {
+			if name := expr.OutputName(v); !contains(next, name) {
+				next = append(next, name)
+			}
+		}
+		return next, true
+	default:
+		return cols, known
+	}
+}
+
+// joinOutputColumns mirrors the column naming of exec.DataFrame.Join.
+func joinOutputColumns(left, right []string, opts exec.JoinOptions) []string {
+	if opts.How == exec.JoinSemi || opts.How == exec.JoinAnti {
+		return left
+	}
+	suffix := opts.Suffix
+	if suffix == "" {
+		suffix = "_right"
+	}
+	_, rightOn := joinKeys(opts)
+	skip := stringSet(rightOn)
+	names := stringSet(left)
+	out := append([]string(nil), left...)
+	for _, c := range right {
+		if _, ok := skip[c]; ok {
+			continue
+		}
+		if _, clash := names[c]; clash {
+			c += suffix
+		}
+		out = append(out, c)
+		names[c] = struct{}{}
+	}
+	return out
+}
+
+func joinKeys(opts exec.JoinOptions) ([]string, []string) {
+	if len(opts.On) > 0 {
+		return opts.On, opts.On
+	}
+	return opts.LeftOn, opts.RightOn
+}
+
+func stringSet(values []string) map[string]struct{} {
+	out := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		out[v] = struct{}{}
+	}
+	return out
+}
+
+func contains(values []string, v string) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+func allIn(values []string, pred func(string) bool) bool {
+	if len(values) == 0 {
+		return false
+	}
+	for _, v := range values {
+		if !pred(v) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,213 @@
+package grizzly
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func joinFixtures(t *testing.T) (*DataFrame, *DataFrame) {
+	t.Helper()
+	left, err := NewDataFrame(
+		MustNewInt64Column("id", []int64{1, 2, 3, 0}, []bool{true, true, true, false}),
+		MustNewUtf8Column("name", []string{"a", "b", "c", "n"}, nil),
+	)
+	if err != nil {
+		t.Fatalf("left: %v", err)
+	}
+	right, err := NewDataFrame(
+		MustNewInt64Column("id", []int64{3, 1, 1, 4, 0}, []bool{true, true, true, true, false}),
+		MustNewUtf8Column("name", []string{"x", "y", "z", "w", "m"}, nil),
+	)
+	if err != nil {
+		t.Fatalf("right: %v", err)
+	}
+	return left, right
+}
+
+func joinRows(df *DataFrame) string {
+	var rows []string
+	cols := df.Columns()
+	for i := 0; i < df.Height(); i++ {
+		vals := make([]string, len(cols))
+		for j, c := range cols {
+			if c.IsNull(i) {
+				vals[j] = "null"
+			} else {
+				vals[j] = c.ValueString(i)
+			}
+		}
+		rows = append(rows, strings.Join(vals, ","))
+	}
+	return strings.Join(rows, ";")
+}
+
+func TestJoinTypes(t *testing.T) {
+	left, right := joinFixtures(t)
+	cases := []struct {
+		how  JoinType
+		want string
+	}{
+		{JoinInner, "1,a,y;1,a,z;3,c,x"},
+		{JoinLeft, "1,a,y;1,a,z;2,b,null;3,c,x;null,n,null"},
+		{JoinRight, "3,c,x;1,a,y;1,a,z;4,null,w;null,null,m"},
+		{JoinFull, "1,a,y;1,a,z;2,b,null;3,c,x;null,n,null;4,null,w;null,null,m"},
+		{JoinSemi, "1,a;3,c"},
+		{JoinAnti, "2,b;null,n"},
+	}
+	for _, tc := range cases {
+		out, err := left.Join(right, JoinOptions{On: []string{"id"}, How: tc.how})
+		if err != nil {
+			t.Fatalf("%s: %v", tc.how, err)
+		}
+		if got := joinRows(out); got != tc.want {
+			t.Fatalf("%s: got %s want %s", tc.how, got, tc.want)
+		}
+	}
+
+	out, err := left.Join(right, JoinOptions{On: []string{"id"}, How: JoinInner, JoinNulls: true, Suffix: "_r"})
+	if err != nil {
+		t.Fatalf("join nulls: %v", err)
+	}
+	if got := joinRows(out); got != "1,a,y;1,a,z;3,c,x;null,n,m" {
+		t.Fatalf("join nulls: got %s", got)
+	}
+	if out.Schema().Fields[2].Name != "name_r" {
+		t.Fatalf("unexpected suffix column %s", out.Schema().Fields[2].Name)
+	}
+}
+
+func TestJoinMultiKeyAndErrors(t *testing.T) {
+	left, err := NewDataFrame(
+		MustNewUtf8Column("k1", []string{"a", "a", "b"}, nil),
+		MustNewBoolColumn("k2", []bool{true, false, true}, nil),
+		MustNewFloat64Column("v", []float64{1, 2, 3}, nil),
+	)
+	if err != nil {
+		t.Fatalf("left: %v", err)
+	}
+	right, err := NewDataFrame(
+		MustNewUtf8Column("rk1", []string{"b", "a"}, nil),
+		MustNewBoolColumn("rk2", []bool{true, false}, nil),
+		MustNewInt64Column("w", []int64{30, 20}, nil),
+	)
+	if err != nil {
+		t.Fatalf("right: %v", err)
+	}
+	out, err := left.Join(right, JoinOptions{LeftOn: []string{"k1", "k2"}, RightOn: []string{"rk1", "rk2"}, How: JoinInner})
+	if err != nil {
+		t.Fatalf("join: %v", err)
+	}
+	if got := joinRows(out); got != "a,false,2,20;b,true,3,30" {
+		t.Fatalf("unexpected rows %s", got)
+	}
+	if _, err := left.Join(right, JoinOptions{LeftOn: []string{"v"}, RightOn: []string{"w"}, How: JoinInner}); err == nil {
+		t.Fatalf("expected dtype mismatch error")
+	}
+}
+
+func TestJoinLargeParallel(t *testing.T) {
+	const n = 200000
+	ids := make([]int64, n)
+	vals := make([]int64, n)
+	for i := range ids {
+		ids[i] = int64(i)
+		vals[i] = int64(i * 2)
+	}
+	left, err := NewDataFrame(MustNewInt64Column("id", ids, nil))
+	if err != nil {
+		t.Fatalf("left: %v", err)
+	}
+	right, err := NewDataFrame(MustNewInt64Column("id", ids[n/2:], nil), MustNewInt64Column("v", vals[n/2:], nil))
+	if err != nil {
+		t.Fatalf("right: %v", err)
+	}
+	out, err := left.Join(right, JoinOptions{On: []string{"id"}, How: JoinLeft})
+	if err != nil {
+		t.Fatalf("join: %v", err)
+	}
+	if out.Height() != n {
+		t.Fatalf("expected %d rows got %d", n, out.Height())
+	}
+	is, _ := out.Column("id")
+	ic, _ := is.Int64()
+	vs, _ := out.Column("v")
+	vc, _ := vs.Int64()
+	for i := 0; i < n; i++ {
+		if ic.Value(i) != int64(i) {
+			t.Fatalf("row %d: order not preserved", i)
+		}
+		if (i < n/2) != vc.IsNull(i) || (i >= n/2 && vc.Value(i) != int64(2*i)) {
+			t.Fatalf("row %d: unexpected value", i)
+		}
+	}
+}
+
+func TestJoinAsof(t *testing.T) {
+	trades, err := NewDataFrame(
+		MustNewInt64Column("t", []int64{1, 5, 10, 12, 20}, nil),
+		MustNewUtf8Column("sym", []string{"a", "a", "b", "a", "b"}, nil),
+	)
+	if err != nil {
+		t.Fatalf("trades: %v", err)
+	}
+	quotes, err := NewDataFrame(
+		MustNewInt64Column("qt", []int64{0, 4, 9, 11, 13}, nil),
+		MustNewUtf8Column("sym", []string{"a", "a", "b", "a", "b"}, nil),
+		MustNewFloat64Column("px", []float64{1.0, 1.5, 7.0, 2.0, 8.0}, nil),
+	)
+	if err != nil {
+		t.Fatalf("quotes: %v", err)
+	}
+	cases := []struct {
+		opts AsofOptions
+		want string
+	}{
+		{AsofOptions{LeftOn: "t", RightOn: "qt", By: []string{"sym"}}, "1,a,0,1;5,a,4,1.5;10,b,9,7;12,a,11,2;20,b,13,8"},
+		{AsofOptions{LeftOn: "t", RightOn: "qt", By: []string{"sym"}, Strategy: AsofForward}, "1,a,4,1.5;5,a,11,2;10,b,13,8;12,a,null,null;20,b,null,null"},
+		{AsofOptions{LeftOn: "t", RightOn: "qt", Strategy: AsofNearest, Tolerance: 1}, "1,a,0,a,1;5,a,4,a,1.5;10,b,9,b,7;12,a,11,a,2;20,b,null,null,null"},
+	}
+	for i, tc := range cases {
+		out, err := trades.JoinAsof(quotes, tc.opts)
+		if err != nil {
+			t.Fatalf("case %d: %v", i, err)
+		}
+		if got := joinRows(out); got != tc.want {
+			t.Fatalf("case %d: got %s want %s", i, got, tc.want)
+		}
+	}
+
+	left, err := NewDataFrame(MustNewInt64Column("t", []int64{0, math.MaxInt64 - 1}, nil))
+	if err != nil {
+		t.Fatalf("left: %v", err)
+	}
+	right, err := NewDataFrame(MustNewInt64Column("qt", []int64{math.MinInt64, math.MaxInt64}, nil))
+	if err != nil {
+		t.Fatalf("right: %v", err)
+	}
+	extremes := []struct {
+		opts AsofOptions
+		want string
+	}{
+		{AsofOptions{LeftOn: "t", RightOn: "qt", Strategy: AsofNearest}, fmt.Sprintf("0,%d;%d,%d", int64(math.MaxInt64), int64(math.MaxInt64-1), int64(math.MaxInt64))},
+		{AsofOptions{LeftOn: "t", RightOn: "qt", Strategy: AsofBackward, Tolerance: 1}, fmt.Sprintf("0,null;%d,null", int64(math.MaxInt64-1))},
+	}
+	for i, tc := range extremes {
+		out, err := left.JoinAsof(right, tc.opts)
+		if err != nil {
+			t.Fatalf("extreme case %d: %v", i, err)
+		}
+		if got := joinRows(out); got != tc.want {
+			t.Fatalf("extreme case %d: got %s want %s", i, got, tc.want)
+		}
+	}
+
+	unsorted, err := NewDataFrame(MustNewInt64Column("qt", []int64{3, 1}, nil))
+	if err != nil {
+		t.Fatalf("unsorted: %v", err)
+	}
+	if _, err := trades.JoinAsof(unsorted, AsofOptions{LeftOn: "t", RightOn: "qt"}); err == nil {
+		t.Fatalf("expected unsorted key error")
+	}
+}
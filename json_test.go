@@ -1,9 +1,14 @@
 package grizzly
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 )
 
@@ -44,3 +49,341 @@ func TestScanJSONCollectContextCanceled(t *testing.T) {
 		t.Fatalf("expected context.Canceled, got %v", err)
 	}
 }
+
+func TestScanJSONHeadStopsEarly(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "x.json")
+	// The array is cut off; only a reader that stops early succeeds.
+	data := `[ {"a": 1}, {"a": 2}, {"a": 3}, {"a": `
+	if err := os.WriteFile(p, []byte(data), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	df, err := ScanJSON(p).Select("a").Head(2).Collect()
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	if got := joinRows(df); got != "1;2" {
+		t.Fatalf("unexpected rows %s", got)
+	}
+	if _, err := ScanJSON(p).Collect(); err == nil {
+		t.Fatalf("expected decode error without a limit")
+	}
+	if _, err := ScanJSON(p).WithColumns(Col("a").FillNullStrategy(FillMean)).Head(2).Collect(); err == nil {
+		t.Fatalf("expected a mean fill to read every record")
+	}
+}
+
+func TestWriteJSONMatchesMarshal(t *testing.T) {
+	const n = 20000
+	ids := make([]int64, n)
+	labels := make([]string, n)
+	valid := make([]bool, n)
+	for i := range ids {
+		ids[i] = int64(i)
+		labels[i] = fmt.Sprintf("row \"%d\"\n", i)
+		valid[i] = i%5 != 0
+	}
+	df, err := NewDataFrame(MustNewInt64Column("id", ids, nil), MustNewUtf8Column("label", labels, valid))
+	if err != nil {
+		t.Fatalf("new dataframe: %v", err)
+	}
+	want, err := df.MarshalRowsJSON()
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var b bytes.Buffer
+	for _, parallel := range []bool{false, true} {
+		prev := runtime.GOMAXPROCS(4)
+		b.Reset()
+		err := df.WriteJSON(&b, JSONWriteOptions{Parallel: parallel})
+		runtime.GOMAXPROCS(prev)
+		if err != nil {
+			t.Fatalf("write json parallel=%v: %v", parallel, err)
+		}
+		if !bytes.Equal(b.Bytes(), want) {
+			t.Fatalf("WriteJSON parallel=%v output differs from MarshalRowsJSON", parallel)
+		}
+	}
+
+	b.Reset()
+	if err := df.WriteNDJSON(&b, JSONWriteOptions{Parallel: true}); err != nil {
+		t.Fatalf("write ndjson: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	if len(lines) != n {
+		t.Fatalf("expected %d lines, got %d", n, len(lines))
+	}
+	var row map[string]any
+	if err := json.Unmarshal([]byte(lines[7]), &row); err != nil {
+		t.Fatalf("line 7: %v", err)
+	}
+	if row["id"] != float64(7) || row["label"] != "row \"7\"\n" {
+		t.Fatalf("unexpected line 7: %s", lines[7])
+	}
+	if !strings.HasSuffix(lines[5], `"label":null}`) {
+		t.Fatalf("expected NULL label on line 5: %s", lines[5])
+	}
+}
+
+func TestWriteJSONEmptyFrame(t *testing.T) {
+	df, err := NewDataFrame(MustNewInt64Column("a", nil, nil))
+	if err != nil {
+		t.Fatalf("new dataframe: %v", err)
+	}
+	var b bytes.Buffer
+	if err := df.WriteJSON(&b, JSONWriteOptions{}); err != nil {
+		t.Fatalf("write json: %v", err)
+	}
+	if b.String() != "[]" {
+		t.Fatalf("expected [], got %q", b.String())
+	}
+	b.Reset()
+	if err := df.WriteNDJSON(&b, JSONWriteOptions{}); err != nil || b.Len() != 0 {
+		t.Fatalf("expected empty ndjson, got %q, %v", b.String(), err)
+	}
+}
+
+func TestScanNDJSONPushdown(t *testing.T) {
+	const n = 60000
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		if i == 10 {
+			b.WriteString("\n")
+		}
+		if i%3 == 0 {
+			fmt.Fprintf(&b, "{\"id\":%d,\"region\":\"east\",\"amount\":%d.5,\"note\":\"a\\\"b\"}\n", i, i%100)
+		} else {
+			fmt.Fprintf(&b, "{\"region\":\"west\",\"id\":%d,\"amount\":null}\n", i)
+		}
+	}
+	p := filepath.Join(t.TempDir(), "x.ndjson")
+	if err := os.WriteFile(p, []byte(b.String()), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	lf := ScanNDJSON(p, JSONScanOptions{}).Filter(Col("region").Eq("east")).Select("id", "note")
+	plan, err := lf.Explain()
+	if err != nil {
+		t.Fatalf("explain: %v", err)
+	}
+	if !strings.Contains(plan, "scan ndjson path="+p+" projection=[id,note] predicate(cols=[region])") {
+		t.Fatalf("expected pushdowns in scan, got:\n%s", plan)
+	}
+	df, err := lf.Collect()
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	if df.Height() != n/3 || len(df.Columns()) != 2 {
+		t.Fatalf("expected %d rows of 2 columns, got %d of %d", n/3, df.Height(), len(df.Columns()))
+	}
+	last, err := df.Slice(n/3-1, 1)
+	if err != nil {
+		t.Fatalf("slice: %v", err)
+	}
+	if got := joinRows(last); got != fmt.Sprintf("%d,a\"b", n-3) {
+		t.Fatalf("unexpected last row %q", got)
+	}
+
+	all, err := ScanNDJSON(p, JSONScanOptions{DTypes: map[string]DataType{"id": Int(32)}}).Head(4).Collect()
+	if err != nil {
+		t.Fatalf("collect all: %v", err)
+	}
+	if got := joinRows(all); got != "0.5,0,a\"b,east;null,1,null,west;null,2,null,west;3.5,3,a\"b,east" {
+		t.Fatalf("unexpected head %q", got)
+	}
+	if all.Columns()[1].DType() != Int(32) {
+		t.Fatalf("expected id as int32, got %s", all.Columns()[1].DType())
+	}
+}
+
+func TestScanNDJSONKeepsFillFilterOutOfScan(t *testing.T) {
+	const n = 60000
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		if i == 0 {
+			fmt.Fprintf(&b, "{\"id\":%d,\"x\":1.5}\n", i)
+		} else {
+			fmt.Fprintf(&b, "{\"id\":%d,\"x\":null}\n", i)
+		}
+	}
+	p := filepath.Join(t.TempDir(), "sparse.ndjson")
+	if err := os.WriteFile(p, []byte(b.String()), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	df, err := ScanNDJSON(p, JSONScanOptions{}).Filter(Col("x").FillNullStrategy(FillMean).Gt(1)).Collect()
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	if df.Height() != n {
+		t.Fatalf("expected every row to take the file-wide mean, got %d rows", df.Height())
+	}
+}
+
+func TestScanNDJSONFieldOutsideSchema(t *testing.T) {
+	var b strings.Builder
+	for i := 0; i < 9000; i++ {
+		fmt.Fprintf(&b, "{\"a\":%d}\n", i)
+	}
+	b.WriteString("{\"a\":1,\"late\":true}\n")
+	p := filepath.Join(t.TempDir(), "x.ndjson")
+	if err := os.WriteFile(p, []byte(b.String()), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	_, err := ScanNDJSON(p, JSONScanOptions{}).Collect()
+	if err == nil || !strings.Contains(err.Error(), "line 9001") || !strings.Contains(err.Error(), `"late"`) {
+		t.Fatalf("expected schema error on line 9001, got %v", err)
+	}
+	df, err := ScanNDJSON(p, JSONScanOptions{}).Select("a").Collect()
+	if err != nil {
+		t.Fatalf("collect with projection: %v", err)
+	}
+	if df.Height() != 9001 || df.Width() != 1 {
+		t.Fatalf("unexpected shape %dx%d", df.Height(), df.Width())
+	}
+	df, err = ScanNDJSON(p, JSONScanOptions{}).Select("a").Head(2).Collect()
+	if err != nil {
+		t.Fatalf("head with projection: %v", err)
+	}
+	if got := joinRows(df); got != "0;1" {
+		t.Fatalf("unexpected rows %q", got)
+	}
+	df, err = ScanNDJSON(p, JSONScanOptions{DTypes: map[string]DataType{"late": Bool()}}).Filter(Col("late").Eq(true)).Collect()
+	if err != nil {
+		t.Fatalf("collect with declared field: %v", err)
+	}
+	if got := joinRows(df); got != "1,true" {
+		t.Fatalf("unexpected rows %q", got)
+	}
+}
+
+func TestScanJSONTypedValues(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "x.json")
+	data := `[
+		{"id": 9007199254740993, "big": 18446744073709551615, "ratio": 1, "ok": true, "code": "007", "meta": {"k": [1, 2]}, "s": "a\"bé"},
+		{"id": -1, "big": 1, "ratio": 2.5e-1, "ok": null, "code": "", "meta": [ ], "s": null}
+	]`
+	if err := os.WriteFile(p, []byte(data), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	df, err := ScanJSON(p).Collect()
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	want := map[string]DataType{"big": UInt(64), "code": Utf8(), "id": Int(64), "meta": Utf8(), "ok": Bool(), "ratio": Float(64), "s": Utf8()}
+	for _, c := range df.Columns() {
+		if c.DType() != want[c.Name()] {
+			t.Fatalf("column %s: expected %s, got %s", c.Name(), want[c.Name()], c.DType())
+		}
+	}
+	if got := joinRows(df); got != `18446744073709551615,007,9007199254740993,{"k":[1,2]},true,1,a"bé;1,,-1,[],null,0.25,null` {
+		t.Fatalf("unexpected rows %q", got)
+	}
+}
+
+func TestScanJSONSchemaBeyondSampleWindow(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("[")
+	for i := 0; i < 9000; i++ {
+		fmt.Fprintf(&b, "{\"a\":%d},", i)
+	}
+	b.WriteString("{\"a\":1,\"late\":\"x\"}]")
+	p := filepath.Join(t.TempDir(), "x.json")
+	if err := os.WriteFile(p, []byte(b.String()), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	df, err := ScanJSON(p).Filter(Col("late").Eq("x")).Collect()
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	if got := joinRows(df); got != "1,x" {
+		t.Fatalf("unexpected rows %q", got)
+	}
+
+	if err := os.WriteFile(p, []byte(strings.Replace(b.String(), `{"a":1,"late":"x"}`, `{"a":1.5}`, 1)), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	_, err = ScanJSON(p).Collect()
+	if err == nil || !strings.Contains(err.Error(), "json record 9001") || !strings.Contains(err.Error(), "does not fit int64") {
+		t.Fatalf("expected type error on record 9001, got %v", err)
+	}
+}
+
+func TestScanJSONRecordPath(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "x.json")
+	data := `{"meta": {"n": 2}, "data": {"a/b": [0, {"items": [{"v": 1}, {"v": 2}]}]}}`
+	if err := os.WriteFile(p, []byte(data), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	for _, path := range []string{"/data/a~1b/1/items", "data.a/b.1.items"} {
+		df, err := ScanJSONWith(p, JSONScanOptions{RecordPath: path}).Collect()
+		if err != nil {
+			t.Fatalf("%s: collect: %v", path, err)
+		}
+		if got := joinRows(df); got != "1;2" {
+			t.Fatalf("%s: unexpected rows %q", path, got)
+		}
+	}
+	if _, err := ScanJSONWith(p, JSONScanOptions{RecordPath: "data.items"}).Collect(); err == nil || !strings.Contains(err.Error(), `no key "items"`) {
+		t.Fatalf("expected missing key error, got %v", err)
+	}
+	df, err := ScanJSON(p).Collect()
+	if err != nil {
+		t.Fatalf("collect root: %v", err)
+	}
+	if df.Height() != 1 || len(df.Columns()) != 2 {
+		t.Fatalf("expected the root object as one row of 2 columns, got %d rows", df.Height())
+	}
+}
+
+func TestScanJSONTasksAndDTypes(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "x.json")
+	data := `{"version": 1, "tasks": [{"id": 1, "cost": 2}, {"id": 2}, 7]}`
+	if err := os.WriteFile(p, []byte(data), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	lf := ScanJSONWith(p, JSONScanOptions{DTypes: map[string]DataType{"cost": Float(64), "id": Int(32)}})
+	plan, err := lf.Explain()
+	if err != nil {
+		t.Fatalf("explain: %v", err)
+	}
+	if !strings.Contains(plan, "scan json path="+p+" dtypes=[cost=float64,id=int32]") {
+		t.Fatalf("expected dtypes in scan, got:\n%s", plan)
+	}
+	df, err := lf.Collect()
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	if got := joinRows(df); got != "2,1,null;null,2,null;null,null,7" {
+		t.Fatalf("unexpected tasks %q", got)
+	}
+	if df.Columns()[0].DType() != Float(64) || df.Columns()[1].DType() != Int(32) {
+		t.Fatalf("dtypes not applied: %s, %s", df.Columns()[0].DType(), df.Columns()[1].DType())
+	}
+}
+
+func TestScanJSONExplodeObject(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "x.json")
+	data := `{"users": {"u1": {"age": 30}, "u2": { }, "u3": {"age": 41, "tag": "x"}}}`
+	if err := os.WriteFile(p, []byte(data), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	lf := ScanJSONWith(p, JSONScanOptions{RecordPath: "users", KeyColumn: "id"}).Head(3)
+	plan, err := lf.Explain()
+	if err != nil {
+		t.Fatalf("explain: %v", err)
+	}
+	if !strings.Contains(plan, "scan json path="+p+" record_path=users key_column=id") {
+		t.Fatalf("expected record options in scan, got:\n%s", plan)
+	}
+	df, err := lf.Collect()
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	if got := joinRows(df); got != "30,u1,null;null,u2,null;41,u3,x" {
+		t.Fatalf("unexpected rows %q", got)
+	}
+	_, err = ScanJSONWith(p, JSONScanOptions{RecordPath: "users", KeyColumn: "age"}).Collect()
+	if err == nil || !strings.Contains(err.Error(), `member "u1" has a field "age"`) {
+		t.Fatalf("expected a key column collision, got %v", err)
+	}
+}
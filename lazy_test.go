@@ -1,8 +1,10 @@
 package grizzly
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -19,3 +21,195 @@ func TestLazyDoesNotChangeSelectFilterSemantics(t *testing.T) {
 		t.Fatalf("expected error")
 	}
 }
+
+func TestLazyGroupByPushesProjection(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "x.csv")
+	data := "k,v,unused\na,1,x\nb,2,y\na,4,z\n"
+	if err := os.WriteFile(p, []byte(data), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	lf := ScanCSV(p, ScanOptions{}).Filter(Col("v").Gt(1)).GroupBy("k").Agg(Sum("v"), Count()).Sort("k", false)
+	plan, err := lf.Explain()
+	if err != nil {
+		t.Fatalf("explain: %v", err)
+	}
+	if !strings.Contains(plan, "projection=[k,v]") || !strings.Contains(plan, "groupby(keys=[k],aggs=[v_sum,count])") {
+		t.Fatalf("unexpected plan:\n%s", plan)
+	}
+	df, err := lf.Collect()
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	if got := joinRows(df); got != "a,4,1;b,2,1" {
+		t.Fatalf("unexpected rows %s", got)
+	}
+
+	_, err = ScanCSV(p, ScanOptions{}).GroupBy("k").Agg(Sum("v")).Filter(Col("v").Eq(1)).Collect()
+	if err == nil {
+		t.Fatalf("expected error filtering on a column dropped by groupby")
+	}
+}
+
+func TestLazyJoinPushesIntoEachSide(t *testing.T) {
+	dir := t.TempDir()
+	orders := filepath.Join(dir, "orders.csv")
+	users := filepath.Join(dir, "users.csv")
+	if err := os.WriteFile(orders, []byte("id,user,amount,note\n1,10,5,x\n2,20,7,y\n3,10,9,z\n4,30,1,w\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.WriteFile(users, []byte("user,name,country,age\n10,ann,de,30\n20,bob,us,40\n30,cid,de,50\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	lf := ScanCSV(orders, ScanOptions{}).
+		Join(ScanCSV(users, ScanOptions{}), JoinOptions{On: []string{"user"}, How: JoinInner}).
+		Filter(Col("amount").Gt(2)).
+		Filter(Col("country").Eq("de")).
+		Select("id", "name")
+	plan, err := lf.Explain()
+	if err != nil {
+		t.Fatalf("explain: %v", err)
+	}
+	want := []string{
+		"select(id,name)",
+		"  join(inner,on=[user])",
+		"    select(user,name)",
+		"      scan csv path=" + users + " null_values=[,NULL,null] projection=[name,user] predicate(cols=[country])",
+		"    select(id,user)",
+		"      scan csv path=" + orders + " null_values=[,NULL,null] projection=[id,user] predicate(cols=[amount])",
+	}
+	if got := strings.TrimRight(plan, "\n"); got != strings.Join(want, "\n") {
+		t.Fatalf("unexpected plan:\n%s", plan)
+	}
+	df, err := lf.Collect()
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	if got := joinRows(df); got != "1,ann;3,ann" {
+		t.Fatalf("unexpected rows %s", got)
+	}
+}
+
+func TestLazyExplainNestedJoin(t *testing.T) {
+	dir := t.TempDir()
+	paths := map[string]string{}
+	for name, data := range map[string]string{
+		"a": "k,x\n1,a\n2,b\n",
+		"b": "k,y\n1,c\n2,d\n",
+		"c": "k,z\n1,e\n",
+		"d": "k,w\n2,f\n1,g\n",
+	} {
+		paths[name] = filepath.Join(dir, name+".csv")
+		if err := os.WriteFile(paths[name], []byte(data), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	scan := func(name string) *LazyFrame { return ScanCSV(paths[name], ScanOptions{}) }
+	on := JoinOptions{On: []string{"k"}, How: JoinInner}
+	lf := scan("a").Join(scan("b").Join(scan("d"), on), on).Join(scan("c"), on)
+	plan, err := lf.Explain()
+	if err != nil {
+		t.Fatalf("explain: %v", err)
+	}
+	want := []string{
+		"join(inner,on=[k])",
+		"  scan csv path=" + paths["c"] + " null_values=[,NULL,null] projection=* (all)",
+		"  join(inner,on=[k])",
+		"    join(inner,on=[k])",
+		"      scan csv path=" + paths["d"] + " null_values=[,NULL,null] projection=* (all)",
+		"      scan csv path=" + paths["b"] + " null_values=[,NULL,null] projection=* (all)",
+		"    scan csv path=" + paths["a"] + " null_values=[,NULL,null] projection=* (all)",
+	}
+	if got := strings.TrimRight(plan, "\n"); got != strings.Join(want, "\n") {
+		t.Fatalf("unexpected plan:\n%s", plan)
+	}
+	df, err := lf.Collect()
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	if got := joinRows(df); got != "1,a,c,g,e" {
+		t.Fatalf("unexpected rows %s", got)
+	}
+}
+
+func TestLazyOptimizerMergesAndOrdersFilters(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "x.csv")
+	data := "k,v\nab,2\nab,3\ncd,5\nax,5\n"
+	if err := os.WriteFile(p, []byte(data), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	lf := ScanCSV(p, ScanOptions{}).
+		GroupBy("k").Agg(Sum("v")).
+		Sort("k", true).
+		Filter(Col("k").Str().Matches("^a")).
+		Filter(And(Col("k").Str().Contains("x"), Col("v_sum").Eq(5)))
+	plan, err := lf.Explain()
+	if err != nil {
+		t.Fatalf("explain: %v", err)
+	}
+	want := []string{
+		"sort(k,desc)",
+		"  filter(cols=[v_sum] & [k] & [k])",
+		"    groupby(keys=[k],aggs=[v_sum])",
+		"      scan csv path=" + p + " null_values=[,NULL,null] projection=[k,v]",
+	}
+	if got := strings.TrimRight(plan, "\n"); got != strings.Join(want, "\n") {
+		t.Fatalf("unexpected plan:\n%s", plan)
+	}
+	df, err := lf.Collect()
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	if got := joinRows(df); got != "ax,5" {
+		t.Fatalf("unexpected rows %s", got)
+	}
+
+	plan, err = ScanCSV(p, ScanOptions{}).Sort("missing", false).Filter(Col("v").Gt(1)).Explain()
+	if err == nil || !strings.Contains(plan, "planning error: sort unknown column missing") {
+		t.Fatalf("expected validation error before rewrites, got %v:\n%s", err, plan)
+	}
+}
+
+func TestLazySortLimitBecomesTopK(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "x.csv")
+	var b strings.Builder
+	b.WriteString("id,score\n")
+	for i := 0; i < 250000; i++ {
+		if i%1000 == 7 {
+			fmt.Fprintf(&b, "%d,\n", i)
+			continue
+		}
+		fmt.Fprintf(&b, "%d,%d\n", i, (i*7919)%1013)
+	}
+	if err := os.WriteFile(p, []byte(b.String()), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	for _, desc := range []bool{false, true} {
+		lf := ScanCSV(p, ScanOptions{}).Sort("score", desc).Slice(3, 20)
+		plan, err := lf.Explain()
+		if err != nil {
+			t.Fatalf("explain: %v", err)
+		}
+		if !strings.Contains(plan, "top_k(score,") || !strings.Contains(plan, ",k=23)") || strings.Contains(plan, "sort(") {
+			t.Fatalf("expected top-k plan:\n%s", plan)
+		}
+		got, err := lf.Collect()
+		if err != nil {
+			t.Fatalf("collect: %v", err)
+		}
+		full, err := ScanCSV(p, ScanOptions{}).Collect()
+		if err != nil {
+			t.Fatalf("collect full: %v", err)
+		}
+		sorted, err := full.SortBy("score", desc)
+		if err != nil {
+			t.Fatalf("sort: %v", err)
+		}
+		want, _ := sorted.Slice(3, 20)
+		if joinRows(got) != joinRows(want) {
+			t.Fatalf("desc=%v: top-k differs from sort+slice\n got %s\nwant %s", desc, joinRows(got), joinRows(want))
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package grizzly
+
+import (
+	"grizzly/internal/array"
+	"grizzly/internal/exec"
+)
+
+// Narrow numeric columns keep values at their native width. Kernels that
+// are specialized on int64 and float64 widen them row by row: arithmetic,
+// sums and means produce int64 (float64 for float32 and uint64 inputs),
+// while sorting, filtering, grouping, min and max work on the stored values.
+
+// numericColumn is the wrapper shared by the narrow numeric column types.
+type numericColumn[T any, C interface {
+	array.Column
+	Value(int) T
+	Values() []T
+}] struct {
+	col C
+}
+
+func (c *numericColumn[T, C]) internalColumn() array.Column { return c.col }
+func (c *numericColumn[T, C]) intoColumn(*exec.DataFrame) (array.Column, error) {
+	return c.col, nil
+}
+
+func (c *numericColumn[T, C]) Name() string      { return c.col.Name() }
+func (c *numericColumn[T, C]) DType() DataType   { return c.col.DType() }
+func (c *numericColumn[T, C]) Len() int          { return c.col.Len() }
+func (c *numericColumn[T, C]) IsNull(i int) bool { return c.col.IsNull(i) }
+func (c *numericColumn[T, C]) Value(i int) T     { return c.col.Value(i) }
+func (c *numericColumn[T, C]) Values() []T       { return c.col.Values() }
+
+type (
+	Int32Column struct {
+		numericColumn[int32, *array.Int32Column]
+	}
+	Int16Column struct {
+		numericColumn[int16, *array.Int16Column]
+	}
+	Int8Column struct {
+		numericColumn[int8, *array.Int8Column]
+	}
+	UInt64Column struct {
+		numericColumn[uint64, *array.UInt64Column]
+	}
+	UInt32Column struct {
+		numericColumn[uint32, *array.UInt32Column]
+	}
+	UInt16Column struct {
+		numericColumn[uint16, *array.UInt16Column]
+	}
+	UInt8Column struct {
+		numericColumn[uint8, *array.UInt8Column]
+	}
+	Float32Column struct {
+		numericColumn[float32, *array.Float32Column]
+	}
+)
+
+func (s Series) Int32() (*Int32Column, bool) {
+	c, ok := s.col.(*array.Int32Column)
+	if !ok {
+		return nil, false
+	}
+	return &Int32Column{numericColumn[int32, *array.Int32Column]{c}}, true
+}
+
+func (s Series) Int16() (*Int16Column, bool) {
+	c, ok := s.col.(*array.Int16Column)
+	if !ok {
+		return nil, false
+	}
+	return &Int16Column{numericColumn[int16, *array.Int16Column]{c}}, true
+}
+
+func (s Series) Int8() (*Int8Column, bool) {
+	c, ok := s.col.(*array.Int8Column)
+	if !ok {
+		return nil, false
+	}
+	return &Int8Column{numericColumn[int8, *array.Int8Column]{c}}, true
+}
+
+func (s Series) UInt64() (*UInt64Column, bool) {
+	c, ok := s.col.(*array.UInt64Column)
+	if !ok {
+		return nil, false
+	}
+	return &UInt64Column{numericColumn[uint64, *array.UInt64Column]{c}}, true
+}
+
+func (s Series) UInt32() (*UInt32Column, bool) {
+	c, ok := s.col.(*array.UInt32Column)
+	if !ok {
+		return nil, false
+	}
+	return &UInt32Column{numericColumn[uint32, *array.UInt32Column]{c}}, true
+}
+
+func (s Series) UInt16() (*UInt16Column, bool) {
+	c, ok := s.col.(*array.UInt16Column)
+	if !ok {
+		return nil, false
+	}
+	return &UInt16Column{numericColumn[uint16, *array.UInt16Column]{c}}, true
+}
+
+func (s Series) UInt8() (*UInt8Column, bool) {
+	c, ok := s.col.(*array.UInt8Column)
+	if !ok {
+		return nil, false
+	}
+	return &UInt8Column{numericColumn[uint8, *array.UInt8Column]{c}}, true
+}
+
+func (s Series) Float32() (*Float32Column, bool) {
+	c, ok := s.col.(*array.Float32Column)
+	if !ok {
+		return nil, false
+	}
+	return &Float32Column{numericColumn[float32, *array.Float32Column]{c}}, true
+}
+
+func NewInt32Column(name string, data []int32, valid []bool) (*Int32Column, error) {
+	c, err := array.NewInt32Column(name, data, valid)
+	if err != nil {
+		return nil, err
+	}
+	return &Int32Column{numericColumn[int32, *array.Int32Column]{c}}, nil
+}
+func MustNewInt32Column(name string, data []int32, valid []bool) *Int32Column {
+	c, err := NewInt32Column(name, data, valid)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func NewInt16Column(name string, data []int16, valid []bool) (*Int16Column, error) {
+	c, err := array.NewInt16Column(name, data, valid)
+	if err != nil {
+		return nil, err
+	}
+	return &Int16Column{numericColumn[int16, *array.Int16Column]{c}}, nil
+}
+func MustNewInt16Column(name string, data []int16, valid []bool) *Int16Column {
+	c, err := NewInt16Column(name, data, valid)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func NewInt8Column(name string, data []int8, valid []bool) (*Int8Column, error) {
+	c, err := array.NewInt8Column(name, data, valid)
+	if err != nil {
+		return nil, err
+	}
+	return &Int8Column{numericColumn[int8, *array.Int8Column]{c}}, nil
+}
+func MustNewInt8Column(name string, data []int8, valid []bool) *Int8Column {
+	c, err := NewInt8Column(name, data, valid)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func NewUInt64Column(name string, data []uint64, valid []bool) (*UInt64Column, error) {
+	c, err := array.NewUInt64Column(name, data, valid)
+	if err != nil {
+		return nil, err
+	}
+	return &UInt64Column{numericColumn[uint64, *array.UInt64Column]{c}}, nil
+}
+func MustNewUInt64Column(name string, data []uint64, valid []bool) *UInt64Column {
+	c, err := NewUInt64Column(name, data, valid)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func NewUInt32Column(name string, data []uint32, valid []bool) (*UInt32Column, error) {
+	c, err := array.NewUInt32Column(name, data, valid)
+	if err != nil {
+		return nil, err
+	}
+	return &UInt32Column{numericColumn[uint32, *array.UInt32Column]{c}}, nil
+}
+func MustNewUInt32Column(name string, data []uint32, valid []bool) *UInt32Column {
+	c, err := NewUInt32Column(name, data, valid)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func NewUInt16Column(name string, data []uint16, valid []bool) (*UInt16Column, error) {
+	c, err := array.NewUInt16Column(name, data, valid)
+	if err != nil {
+		return nil, err
+	}
+	return &UInt16Column{numericColumn[uint16, *array.UInt16Column]{c}}, nil
+}
+func MustNewUInt16Column(name string, data []uint16, valid []bool) *UInt16Column {
+	c, err := NewUInt16Column(name, data, valid)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func NewUInt8Column(name string, data []uint8, valid []bool) (*UInt8Column, error) {
+	c, err := array.NewUInt8Column(name, data, valid)
+	if err != nil {
+		return nil, err
+	}
+	return &UInt8Column{numericColumn[uint8, *array.UInt8Column]{c}}, nil
+}
+func MustNewUInt8Column(name string, data []uint8, valid []bool) *UInt8Column {
+	c, err := NewUInt8Column(name, data, valid)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func NewFloat32Column(name string, data []float32, valid []bool) (*Float32Column, error) {
+	c, err := array.NewFloat32Column(name, data, valid)
+	if err != nil {
+		return nil, err
+	}
+	return &Float32Column{numericColumn[float32, *array.Float32Column]{c}}, nil
+}
+func MustNewFloat32Column(name string, data []float32, valid []bool) *Float32Column {
+	c, err := NewFloat32Column(name, data, valid)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
@@ -1,8 +1,10 @@
 package grizzly
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -35,3 +37,108 @@ func TestSortStableForEqualKeys(t *testing.T) {
 		}
 	}
 }
+
+func TestTopKBottomK(t *testing.T) {
+	const n = 150000
+	lat := make([]int64, n)
+	valid := make([]bool, n)
+	host := make([]string, n)
+	for i := range lat {
+		lat[i] = int64((i * 7919) % 5003)
+		valid[i] = i%97 != 0
+		host[i] = fmt.Sprintf("h%d", i%3)
+	}
+	l, err := NewInt64Column("latency", lat, valid)
+	if err != nil {
+		t.Fatalf("new column: %v", err)
+	}
+	df, err := NewDataFrame(l, MustNewUtf8Column("host", host, nil))
+	if err != nil {
+		t.Fatalf("new dataframe: %v", err)
+	}
+
+	top, err := df.TopK(50, []string{"latency", "host"}, true)
+	if err != nil {
+		t.Fatalf("top k: %v", err)
+	}
+	if top.Height() != 50 {
+		t.Fatalf("expected 50 rows, got %d", top.Height())
+	}
+	s, _ := top.Column("latency")
+	lc, _ := s.Int64()
+	if lc.Value(0) != 5002 || lc.IsNull(49) {
+		t.Fatalf("unexpected top rows: first=%d", lc.Value(0))
+	}
+	for i := 1; i < lc.Len(); i++ {
+		if lc.Value(i) > lc.Value(i-1) {
+			t.Fatalf("top k not ordered at %d", i)
+		}
+	}
+
+	bottom, err := df.BottomK(3, []string{"latency"}, true)
+	if err != nil {
+		t.Fatalf("bottom k: %v", err)
+	}
+	sorted, err := df.SortBy("latency", false)
+	if err != nil {
+		t.Fatalf("sort: %v", err)
+	}
+	want, _ := sorted.Head(3)
+	if joinRows(bottom) != joinRows(want) {
+		t.Fatalf("bottom k %s differs from sort head %s", joinRows(bottom), joinRows(want))
+	}
+
+	if _, err := df.TopK(1, []string{"missing"}, false); err == nil {
+		t.Fatalf("expected unknown column error")
+	}
+}
+
+func TestSortMultipleKeys(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "sales.csv")
+	data := "id,region,revenue\n1,west,10\n2,east,\n3,west,30\n4,east,20\n5,west,\n6,east,20\n7,west,10\n"
+	if err := os.WriteFile(p, []byte(data), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	keys := []SortKey{{Col: "region"}, {Col: "revenue", Desc: true, NullsFirst: true}}
+	want := []int64{2, 4, 6, 5, 3, 1, 7}
+
+	full, err := ScanCSV(p, ScanOptions{}).Collect()
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	sorted, err := full.SortKeys(keys, SortOptions{MaintainOrder: true})
+	if err != nil {
+		t.Fatalf("sort: %v", err)
+	}
+	lazy, err := ScanCSV(p, ScanOptions{}).SortKeys(keys, SortOptions{}).Head(4).Collect()
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	for _, tc := range []struct {
+		df   *DataFrame
+		want []int64
+	}{{sorted, want}, {lazy, want[:4]}} {
+		s, _ := tc.df.Column("id")
+		ids, _ := s.Int64()
+		if ids.Len() != len(tc.want) {
+			t.Fatalf("expected %d rows, got %d", len(tc.want), ids.Len())
+		}
+		for i, id := range tc.want {
+			if ids.Value(i) != id {
+				t.Fatalf("row %d: got id %d want %d", i, ids.Value(i), id)
+			}
+		}
+	}
+
+	plan, err := ScanCSV(p, ScanOptions{}).SortKeys(keys, SortOptions{}).Head(4).Explain()
+	if err != nil {
+		t.Fatalf("explain: %v", err)
+	}
+	if !strings.Contains(plan, "top_k(region,asc;revenue,desc,nulls_first,k=4)") {
+		t.Fatalf("expected fused multi-key top_k, got:\n%s", plan)
+	}
+	if _, err := full.SortKeys(nil, SortOptions{}); err == nil {
+		t.Fatalf("expected error for sort without keys")
+	}
+}
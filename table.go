@@ -282,6 +282,17 @@ func statsForColumn(col array.Column, n int) string {
 	nulls := 0
 	nonNull := 0
 
+	// Narrow numeric columns report the stats of their widened values.
+	if dt := col.DType(); array.IsNumeric(dt) && dt != array.Int(64) && dt != array.Float(64) {
+		to := array.Float(64)
+		if _, ok := array.Int64Accessor(col); ok {
+			to = array.Int(64)
+		}
+		if wide, err := array.Cast(col, to); err == nil {
+			col = wide
+		}
+	}
+
 	switch c := col.(type) {
 	case *array.Int64Column:
 		min := int64(0)
@@ -394,6 +405,28 @@ func statsForColumn(col array.Column, n int) string {
 		}
 		return fmt.Sprintf("nulls=%d/%d len=[%d,%d]", nulls, n, minLen, maxLen)
 
+	case *array.DateColumn, *array.DatetimeColumn, *array.DurationColumn:
+		minRow, maxRow := -1, -1
+		var minV, maxV int64
+		for i := 0; i < n; i++ {
+			if c.IsNull(i) {
+				nulls++
+				continue
+			}
+			nonNull++
+			v := temporalRaw(c, i)
+			if minRow < 0 || v < minV {
+				minRow, minV = i, v
+			}
+			if maxRow < 0 || v > maxV {
+				maxRow, maxV = i, v
+			}
+		}
+		if nonNull == 0 {
+			return fmt.Sprintf("nulls=%d/%d", nulls, n)
+		}
+		return fmt.Sprintf("nulls=%d/%d min=%s max=%s", nulls, n, c.ValueString(minRow), c.ValueString(maxRow))
+
 	default:
 		for i := 0; i < n; i++ {
 			if col.IsNull(i) {
@@ -406,6 +439,19 @@ func statsForColumn(col array.Column, n int) string {
 	}
 }
 
+func temporalRaw(col array.Column, i int) int64 {
+	switch c := col.(type) {
+	case *array.DateColumn:
+		return int64(c.Value(i))
+	case *array.DatetimeColumn:
+		return c.Value(i)
+	case *array.DurationColumn:
+		return c.Value(i)
+	default:
+		return 0
+	}
+}
+
 func pad(s string, width int) string {
 	if len(s) >= width {
 		return s
@@ -0,0 +1,95 @@
+package grizzly
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTemporalColumnsSortFilterJSON(t *testing.T) {
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	ts := MustNewDatetimeColumn("ts", TimeUnitUS, "", []time.Time{
+		base.Add(2 * time.Hour),
+		base,
+		base.Add(time.Hour),
+	}, nil)
+	day := MustNewDateColumn("day", []time.Time{base, base.AddDate(0, 0, 1), base.AddDate(0, 0, 2)}, nil)
+	dur := MustNewDurationColumn("dur", TimeUnitMS, []time.Duration{time.Second, 0, 90 * time.Minute}, []bool{true, false, true})
+	df, err := NewDataFrame(ts, day, dur)
+	if err != nil {
+		t.Fatalf("new dataframe: %v", err)
+	}
+	if got := df.Schema().Fields[0].Type.String(); got != "datetime[us]" {
+		t.Fatalf("unexpected dtype %s", got)
+	}
+
+	sorted, err := df.SortBy("ts", false)
+	if err != nil {
+		t.Fatalf("sort: %v", err)
+	}
+	s, _ := sorted.Column("ts")
+	dc, ok := s.Datetime()
+	if !ok {
+		t.Fatalf("expected datetime column")
+	}
+	if !dc.Value(0).Equal(base) || !dc.Value(2).Equal(base.Add(2*time.Hour)) {
+		t.Fatalf("unexpected sort order: %v", dc.Values())
+	}
+
+	filtered, err := df.Filter(Col("ts").Gt(base.Add(30 * time.Minute)))
+	if err != nil {
+		t.Fatalf("filter datetime: %v", err)
+	}
+	if filtered.Height() != 2 {
+		t.Fatalf("expected 2 rows got %d", filtered.Height())
+	}
+	filtered, err = df.Filter(Col("day").Gte("2024-03-02"))
+	if err != nil {
+		t.Fatalf("filter date: %v", err)
+	}
+	if filtered.Height() != 2 {
+		t.Fatalf("expected 2 rows got %d", filtered.Height())
+	}
+	filtered, err = df.Filter(Col("dur").Gt(time.Minute))
+	if err != nil {
+		t.Fatalf("filter duration: %v", err)
+	}
+	if filtered.Height() != 1 {
+		t.Fatalf("expected 1 row got %d", filtered.Height())
+	}
+
+	b, err := df.Head(1)
+	if err != nil {
+		t.Fatalf("head: %v", err)
+	}
+	js, err := b.MarshalRowsJSON()
+	if err != nil {
+		t.Fatalf("json: %v", err)
+	}
+	want := `[{"ts":"2024-03-01T14:00:00","day":"2024-03-01","dur":1000}]`
+	if string(js) != want {
+		t.Fatalf("unexpected json %s", js)
+	}
+	if out := df.String(); !strings.Contains(out, "2024-03-03") || !strings.Contains(out, "1h30m0s") {
+		t.Fatalf("unexpected table output: %s", out)
+	}
+}
+
+func TestDatetimeColumnTimeZone(t *testing.T) {
+	v := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c, err := NewDatetimeColumn("ts", TimeUnitMS, "America/New_York", []time.Time{v}, nil)
+	if err != nil {
+		t.Skipf("tz database unavailable: %v", err)
+	}
+	df, err := NewDataFrame(c)
+	if err != nil {
+		t.Fatalf("new dataframe: %v", err)
+	}
+	s, _ := df.Column("ts")
+	if got := s.ValueString(0); got != "2023-12-31T19:00:00-05:00" {
+		t.Fatalf("unexpected rendering %s", got)
+	}
+	if _, err := NewDatetimeColumn("ts", TimeUnitInvalid, "", []time.Time{v}, nil); err == nil {
+		t.Fatalf("expected invalid unit error")
+	}
+}